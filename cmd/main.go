@@ -1,58 +1,138 @@
-package main
-
-import (
-	// "fmt"
-	"log"
-	"net"
-
-	"github.com/tanmaygupta069/order-service-go/config"
-	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
-	HoldingPb "github.com/tanmaygupta069/order-service-go/generated/holding"
-	"github.com/tanmaygupta069/order-service-go/internal/holding"
-	"github.com/tanmaygupta069/order-service-go/internal/order"
-
-	// "github.com/tanmaygupta069/order-service-go/pkg/mysql"
-
-	// Redis "github.com/tanmaygupta069/order-service-go/pkg/redis"
-	"google.golang.org/grpc"
-
-	// "google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/reflection"
-)
-
-func main() {
-	// Redis.InitializeRedisClient()
-	// mysql.InitializeSqlClient()
-	cfg, err := config.GetConfig()
-	if err != nil {
-		log.Printf("error: %v", err.Error())
-	}
-	orderController := order.NewOrderController()
-	if err != nil {
-		log.Fatalf("Failed to load TLS keys: %v", err)
-	}
-	holdingController := holding.NewHoldingController()
-	if err != nil {
-		log.Fatalf("Failed to load TLS keys: %v", err)
-	}
-	listener, err := net.Listen("tcp4", ":"+cfg.GrpcConfig.Port)
-	if err != nil {
-		log.Fatalf("Failed to listen: %v", err)
-	}
-
-	// _, er := credentials.NewServerTLSFromFile("cert.pem", "key.pem")
-	// if er != nil {
-	// 	fmt.Printf("error in parsing certificate")
-	// }
-	grpcServer := grpc.NewServer()
-	OrderPb.RegisterOrderServiceServer(grpcServer, orderController)
-	HoldingPb.RegisterHoldingServiceServer(grpcServer,holdingController)
-	reflection.Register(grpcServer)
-
-
-
-	log.Printf("gRPC server is running on port %s", cfg.GrpcConfig.Port)
-	if err := grpcServer.Serve(listener); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
-	}
-}
+package main
+
+import (
+	// "fmt"
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
+	OrderV2Pb "github.com/tanmaygupta069/order-service-go/generated/order/v2"
+	HoldingPb "github.com/tanmaygupta069/order-service-go/generated/holding"
+	CredentialPb "github.com/tanmaygupta069/order-service-go/generated/credential"
+	"github.com/tanmaygupta069/order-service-go/internal/credential"
+	"github.com/tanmaygupta069/order-service-go/internal/holding"
+	"github.com/tanmaygupta069/order-service-go/internal/order"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/audit"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/gateway"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/ratelimit"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/tlsconfig"
+	"github.com/tanmaygupta069/order-service-go/internal/security"
+
+	// "github.com/tanmaygupta069/order-service-go/pkg/mysql"
+
+	// Redis "github.com/tanmaygupta069/order-service-go/pkg/redis"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+)
+
+// reflectionPolicies keeps server reflection open so grpcurl and friends can list services.
+var reflectionPolicies = map[string]string{
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      auth.POLICY_PUBLIC,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": auth.POLICY_PUBLIC,
+}
+
+func main() {
+	// Redis.InitializeRedisClient()
+	// mysql.InitializeSqlClient()
+	cfg, err := config.GetConfig()
+	if err != nil {
+		log.Printf("error: %v", err.Error())
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatalf("Failed to migrate: %v", err)
+		}
+		return
+	}
+	if cfg.SecuritiesCsv != "" {
+		count, err := security.NewSecurityService().LoadFromCSV(context.Background(), cfg.SecuritiesCsv)
+		if err != nil {
+			log.Fatalf("Failed to load securities: %v", err)
+		}
+		log.Printf("loaded %d securities from %s", count, cfg.SecuritiesCsv)
+	}
+	if cfg.MarketConfig.ExpirySweepSeconds > 0 {
+		go order.ExpireDayOrdersEvery(context.Background(), order.NewOrderService(), time.Duration(cfg.MarketConfig.ExpirySweepSeconds)*time.Second)
+	}
+	orderController := order.NewOrderController()
+	orderControllerV2 := order.NewOrderControllerV2()
+	if err != nil {
+		log.Fatalf("Failed to load TLS keys: %v", err)
+	}
+	holdingController := holding.NewHoldingController()
+	if err != nil {
+		log.Fatalf("Failed to load TLS keys: %v", err)
+	}
+	credentialController := credential.NewCredentialController()
+	listener, err := net.Listen("tcp4", ":"+cfg.GrpcConfig.Port)
+	if err != nil {
+		log.Fatalf("Failed to listen: %v", err)
+	}
+
+	authInterceptor := auth.NewAuthInterceptor(auth.NewAuthPackage(), audit.NewAuditService(), order.MethodPolicies, holding.MethodPolicies, credential.MethodPolicies, reflectionPolicies)
+	rateLimiter := ratelimit.NewRateLimitInterceptor(ratelimit.NewRateLimiter(cfg.RateLimiterConfig))
+	peerLimiter := ratelimit.NewPeerRateLimitInterceptor(ratelimit.NewRateLimiter(config.RateLimiterConfig{
+		RateLimit:  cfg.RateLimiterConfig.PeerRateLimit,
+		BucketSize: cfg.RateLimiterConfig.PeerBucketSize,
+	}))
+	// errors are mapped innermost so auditing sees the same result the client gets
+	errorInterceptor := apperror.NewErrorInterceptor(cfg.GrpcConfig.LegacyResponses)
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(peerLimiter.Unary(), authInterceptor.Unary(), rateLimiter.Unary(), errorInterceptor.Unary()),
+		grpc.ChainStreamInterceptor(peerLimiter.Stream(), authInterceptor.Stream(), rateLimiter.Stream(), errorInterceptor.Stream()),
+	}
+	var reloader *tlsconfig.CertReloader
+	if cfg.TlsConfig.CertFile != "" {
+		reloader, err = tlsconfig.NewCertReloader(cfg.TlsConfig)
+		if err != nil {
+			log.Fatalf("Failed to load TLS keys: %v", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+		log.Printf("TLS enabled, mutual TLS: %t", cfg.TlsConfig.ClientCaFile != "")
+	} else {
+		log.Printf("TLS_CERT_FILE not set, serving plaintext")
+	}
+	grpcServer := grpc.NewServer(serverOptions...)
+	OrderPb.RegisterOrderServiceServer(grpcServer, orderController)
+	OrderV2Pb.RegisterOrderServiceServer(grpcServer, orderControllerV2)
+	HoldingPb.RegisterHoldingServiceServer(grpcServer,holdingController)
+	CredentialPb.RegisterCredentialServiceServer(grpcServer, credentialController)
+	reflection.Register(grpcServer)
+
+	if cfg.ServerConfig.Port != "" {
+		handler, err := gateway.NewGateway(context.Background(), cfg)
+		if err != nil {
+			log.Fatalf("Failed to start gateway: %v", err)
+		}
+		httpServer := &http.Server{Addr: ":" + cfg.ServerConfig.Port, Handler: handler}
+		go func() {
+			var err error
+			if reloader != nil {
+				httpServer.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: reloader.GetCertificate}
+				err = httpServer.ListenAndServeTLS("", "")
+			} else {
+				err = httpServer.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve gateway: %v", err)
+			}
+		}()
+		log.Printf("REST gateway is running on port %s", cfg.ServerConfig.Port)
+	}
+
+
+
+	log.Printf("gRPC server is running on port %s", cfg.GrpcConfig.Port)
+	if err := grpcServer.Serve(listener); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
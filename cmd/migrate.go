@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+const migrateUsage = "usage: main migrate up | down [steps] | status"
+
+// runMigrate is the migrate subcommand, it changes the schema and exits without serving.
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	db := mysql.GetSqlClient()
+	if db == nil {
+		return fmt.Errorf("no database connection")
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := mysql.MigrateUp(ctx, db)
+		for _, migration := range applied {
+			log.Printf("applied %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Printf("schema is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("steps must be a positive number, got %s", args[1])
+			}
+			steps = n
+		}
+		rolledBack, err := mysql.MigrateDown(ctx, db, steps)
+		for _, migration := range rolledBack {
+			log.Printf("rolled back %04d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(rolledBack) == 0 {
+			log.Printf("no applied migrations to roll back")
+		}
+	case "status":
+		states, err := mysql.MigrationStatus(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", state.Version, state.Name, applied)
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
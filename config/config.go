@@ -1,64 +1,171 @@
-package config
-
-import (
-	"fmt"
-	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
-)
-
-func GetConfig()(*Config,error){
-	err:=godotenv.Load()
-	if err!=nil{
-		fmt.Printf("No .env file found : %v",err)
-		return nil,err
-	}
-	config:=&Config{
-		ServerConfig: ServerConfig{
-			Port: getEnv("PORT"),
-		},
-		RateLimiterConfig: RateLimiterConfig{
-			RateLimit:   getEnvInt("RATE_LIMIT",2),
-			BucketSize: getEnvInt("BUCKET_SIZE",10),
-		},
-		MySqlConfig: MySqlConfig{
-			Port: getEnvInt("MYSQL_PORT",3306),
-			User: getEnv("MYSQL_USER"),
-			Password: getEnv("MYSQL_PASS"),
-			Database: getEnv("MYSQL_DB"),
-			Host: getEnv("MYSQL_HOST"),
-		},
-		RedisConfig:RedisConfig{
-			Port: getEnv("REDIS_PORT"),
-			User: getEnv("REDIS_USER"),
-			Db: getEnvInt("REDIS_DB",0),
-			Password: getEnv("REDIS_PASS"),
-			Host: getEnv("REDIS_HOST"),
-		},
-		GrpcConfig: GrpcConfig{
-			Port: getEnv("ORDER_SERVICE_PORT"),
-			User: getEnv("ORDER_SERVICE_USER"),
-		},
-		JwtSecret : getEnv("JWT_SECRET"),
-		StockApiKey: getEnv("STOCK_API_KEY"),
-	}
-	return config,nil
-}
-
-func getEnv(key string)string{
-	if val,exisit := os.LookupEnv(key);exisit{
-		return val
-	}
-	fmt.Printf("\n%s not found in .env\n",key)
-	return "";
-}
-
-func getEnvInt(key string,Default int)int{
-	if val,exisit := os.LookupEnv(key);exisit{
-		res,_ := strconv.Atoi(val)
-		return res
-	}
-	fmt.Printf("\n%s not found in .env\n",key)
-	return Default;
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+func GetConfig()(*Config,error){
+	err:=godotenv.Load()
+	if err!=nil{
+		fmt.Printf("No .env file found : %v",err)
+		return nil,err
+	}
+	config:=&Config{
+		ServerConfig: ServerConfig{
+			Port: getEnv("PORT"),
+			GrpcEndpoint: getEnv("GATEWAY_GRPC_ENDPOINT"),
+			GrpcCaFile: getEnv("GATEWAY_GRPC_CA_FILE"),
+			ClientCertFile: getEnv("GATEWAY_CLIENT_CERT_FILE"),
+			ClientKeyFile: getEnv("GATEWAY_CLIENT_KEY_FILE"),
+		},
+		RateLimiterConfig: RateLimiterConfig{
+			RateLimit:   getEnvInt("RATE_LIMIT",2),
+			BucketSize: getEnvInt("BUCKET_SIZE",10),
+			Overrides: getEnvList("RATE_LIMIT_OVERRIDES",[]string{}),
+			PeerRateLimit: getEnvInt("PEER_RATE_LIMIT",20),
+			PeerBucketSize: getEnvInt("PEER_BUCKET_SIZE",40),
+		},
+		MySqlConfig: MySqlConfig{
+			Port: getEnvInt("MYSQL_PORT",3306),
+			User: getEnv("MYSQL_USER"),
+			Password: getEnv("MYSQL_PASS"),
+			Database: getEnv("MYSQL_DB"),
+			Host: getEnv("MYSQL_HOST"),
+			Driver: getEnvDefault("DB_DRIVER",DRIVER_MYSQL),
+			SqlitePath: getEnvDefault("SQLITE_PATH",":memory:"),
+		},
+		RedisConfig:RedisConfig{
+			Port: getEnv("REDIS_PORT"),
+			User: getEnv("REDIS_USER"),
+			Db: getEnvInt("REDIS_DB",0),
+			Password: getEnv("REDIS_PASS"),
+			Host: getEnv("REDIS_HOST"),
+		},
+		GrpcConfig: GrpcConfig{
+			Port: getEnv("ORDER_SERVICE_PORT"),
+			User: getEnv("ORDER_SERVICE_USER"),
+			LegacyResponses: getEnvBool("LEGACY_ERROR_RESPONSES",true),
+		},
+		JwtSecret : getEnv("JWT_SECRET"),
+		JwtConfig: JwtConfig{
+			JwksUrl: getEnv("JWKS_URL"),
+			JwksFile: getEnv("JWKS_FILE"),
+			JwksRefreshMinutes: getEnvInt("JWKS_REFRESH_MINUTES",10),
+			Issuer: getEnv("JWT_ISSUER"),
+			Audience: getEnv("JWT_AUDIENCE"),
+			ClockSkewSeconds: getEnvInt("JWT_CLOCK_SKEW_SECONDS",30),
+			RequireExpiry: getEnvBool("JWT_REQUIRE_EXP",true),
+			MaxLifetimeMinutes: getEnvInt("JWT_MAX_LIFETIME_MINUTES",24*60),
+		},
+		StockApiKey: getEnv("STOCK_API_KEY"),
+		PriceConfig: PriceConfig{
+			Providers: getEnvList("PRICE_PROVIDERS",[]string{"finnhub"}),
+			CrossCheck: getEnvBool("PRICE_CROSS_CHECK",false),
+			MaxDivergencePct: getEnvFloat("PRICE_MAX_DIVERGENCE_PCT",5),
+			AlphaVantageApiKey: getEnv("ALPHA_VANTAGE_API_KEY"),
+		},
+		SecuritiesCsv: getEnv("SECURITIES_CSV"),
+		MarketConfig: MarketConfig{
+			CalendarFile: getEnv("MARKET_CALENDAR_FILE"),
+			QueueOutsideHours: getEnvBool("QUEUE_OUTSIDE_MARKET_HOURS",false),
+			ExpirySweepSeconds: getEnvInt("DAY_ORDER_EXPIRY_SWEEP_SECONDS",60),
+		},
+		RiskConfigFile: getEnv("RISK_CONFIG_FILE"),
+		HaltConfig: HaltConfig{
+			CircuitBreakerPct: getEnvFloat("CIRCUIT_BREAKER_PCT",10),
+			WindowMinutes: getEnvInt("CIRCUIT_BREAKER_WINDOW_MINUTES",5),
+			AutoHaltMinutes: getEnvInt("CIRCUIT_BREAKER_HALT_MINUTES",15),
+		},
+		TlsConfig: TlsConfig{
+			CertFile: getEnv("TLS_CERT_FILE"),
+			KeyFile: getEnv("TLS_KEY_FILE"),
+			ClientCaFile: getEnv("TLS_CLIENT_CA_FILE"),
+			ClientAuth: getEnv("TLS_CLIENT_AUTH"),
+			ReloadSeconds: getEnvInt("TLS_RELOAD_SECONDS",30),
+			ClientIdentities: getEnvList("TLS_CLIENT_IDENTITIES",[]string{}),
+		},
+		OrderHistoryConfig: OrderHistoryConfig{
+			DefaultPageSize: getEnvInt("ORDER_HISTORY_PAGE_SIZE",50),
+			MaxPageSize: getEnvInt("ORDER_HISTORY_MAX_PAGE_SIZE",500),
+		},
+		TimeoutConfig: TimeoutConfig{
+			DatabaseMs: getEnvInt("DB_TIMEOUT_MS",5000),
+			RedisMs: getEnvInt("REDIS_TIMEOUT_MS",1000),
+			PriceProviderMs: getEnvInt("PRICE_PROVIDER_TIMEOUT_MS",5000),
+		},
+		StorageConfig: StorageConfig{
+			RepositoryBackend: getEnvDefault("REPOSITORY_BACKEND",BACKEND_SQL),
+			CacheBackend: getEnvDefault("CACHE_BACKEND",BACKEND_REDIS),
+		},
+	}
+	return config,nil
+}
+
+func getEnv(key string)string{
+	if val,exisit := os.LookupEnv(key);exisit{
+		return val
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return "";
+}
+
+func getEnvDefault(key string,Default string)string{
+	if val,exisit := os.LookupEnv(key);exisit{
+		return val
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return Default;
+}
+
+func getEnvInt(key string,Default int)int{
+	if val,exisit := os.LookupEnv(key);exisit{
+		res,_ := strconv.Atoi(val)
+		return res
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return Default;
+}
+
+func getEnvFloat(key string,Default float64)float64{
+	if val,exisit := os.LookupEnv(key);exisit{
+		res,err := strconv.ParseFloat(val,64)
+		if err!=nil{
+			return Default
+		}
+		return res
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return Default;
+}
+
+func getEnvBool(key string,Default bool)bool{
+	if val,exisit := os.LookupEnv(key);exisit{
+		res,err := strconv.ParseBool(val)
+		if err!=nil{
+			return Default
+		}
+		return res
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return Default;
+}
+
+// getEnvList reads a comma separated value, dropping blank entries.
+func getEnvList(key string,Default []string)[]string{
+	if val,exisit := os.LookupEnv(key);exisit{
+		res := make([]string,0)
+		for _,item := range strings.Split(val,","){
+			if item = strings.TrimSpace(item);item!=""{
+				res = append(res,item)
+			}
+		}
+		return res
+	}
+	fmt.Printf("\n%s not found in .env\n",key)
+	return Default;
 }
\ No newline at end of file
@@ -1,41 +1,152 @@
-package config
-
-type Config struct{
-	ServerConfig ServerConfig
-	RateLimiterConfig RateLimiterConfig
-	MySqlConfig MySqlConfig
-	RedisConfig RedisConfig
-	GrpcConfig GrpcConfig 
-	JwtSecret string
-	StockApiKey string 
-}
-
-type GrpcConfig struct{
-	Port string
-	User string
-}
- 
-type ServerConfig struct{
-	Port string
-}
-
-type RedisConfig struct{
-	Port string
-	User string
-	Db int
-	Password string
-	Host string
-}
-
-type RateLimiterConfig struct{
-	RateLimit int
-	BucketSize int
-}
-
-type MySqlConfig struct{
-	Port int
-    User string
-    Password string
-    Database string
-	Host string
+package config
+
+type Config struct{
+	ServerConfig ServerConfig
+	RateLimiterConfig RateLimiterConfig
+	MySqlConfig MySqlConfig
+	RedisConfig RedisConfig
+	GrpcConfig GrpcConfig 
+	JwtSecret string
+	JwtConfig JwtConfig
+	StockApiKey string 
+	PriceConfig PriceConfig
+	SecuritiesCsv string
+	MarketConfig MarketConfig
+	RiskConfigFile string
+	HaltConfig HaltConfig
+	TlsConfig TlsConfig
+	OrderHistoryConfig OrderHistoryConfig
+	TimeoutConfig TimeoutConfig
+	StorageConfig StorageConfig
+}
+
+const (
+	DRIVER_MYSQL  = "mysql"
+	DRIVER_SQLITE = "sqlite"
+)
+
+const (
+	BACKEND_SQL    = "sql"
+	BACKEND_REDIS  = "redis"
+	BACKEND_MEMORY = "memory"
+)
+
+// StorageConfig picks where the order and holding repositories keep their rows, "sql"
+// or "memory", and what backs the cache, "redis" or "memory". Securities, api keys and
+// audit logs stay in the SQL database either way. The memory backends lose everything on restart and
+// aren't shared between replicas, they are meant for tests and local development only.
+type StorageConfig struct{
+	RepositoryBackend string
+	CacheBackend string
+}
+
+// TimeoutConfig bounds every single database, redis and price provider call in
+// milliseconds, on top of whatever deadline the caller's context has. 0 is no bound.
+type TimeoutConfig struct{
+	DatabaseMs int
+	RedisMs int
+	PriceProviderMs int
+}
+
+// OrderHistoryConfig is the page size of order history when the client doesn't ask for
+// one, and the most it can ask for.
+type OrderHistoryConfig struct{
+	DefaultPageSize int
+	MaxPageSize int
+}
+
+// TlsConfig turns on TLS when CertFile is set and mutual TLS when ClientCaFile is set.
+// ClientAuth is "require" (the default) or "optional". ClientIdentities maps a client
+// certificate identity to roles as identity=role|role.
+type TlsConfig struct{
+	CertFile string
+	KeyFile string
+	ClientCaFile string
+	ClientAuth string
+	ReloadSeconds int
+	ClientIdentities []string
+}
+
+type HaltConfig struct{
+	CircuitBreakerPct float64
+	WindowMinutes int
+	AutoHaltMinutes int
+}
+
+// ExpirySweepSeconds is how often placed day orders are checked for a closed trading
+// day, 0 leaves expiring them to the fills.
+type MarketConfig struct{
+	CalendarFile string
+	QueueOutsideHours bool
+	ExpirySweepSeconds int
+}
+
+type PriceConfig struct{
+	Providers []string
+	CrossCheck bool
+	MaxDivergencePct float64
+	AlphaVantageApiKey string
+}
+
+// JwtConfig.MaxLifetimeMinutes is the longest exp - iat accepted and how long revocations
+// are kept. Zero accepts tokens of any lifetime and keeps revocations for good.
+type JwtConfig struct{
+	JwksUrl string
+	JwksFile string
+	JwksRefreshMinutes int
+	Issuer string
+	Audience string
+	ClockSkewSeconds int
+	RequireExpiry bool
+	MaxLifetimeMinutes int
+}
+
+// LegacyResponses keeps failures in common.Response with a nil error instead of
+// returning gRPC status errors.
+type GrpcConfig struct{
+	Port string
+	User string
+	LegacyResponses bool
+}
+ 
+// ServerConfig is the HTTP/JSON gateway. It calls the gRPC server at GrpcEndpoint,
+// localhost on the gRPC port by default, so every call goes through the same interceptors.
+// The CA and client certificate are only needed when the gRPC server uses TLS or mTLS.
+type ServerConfig struct{
+	Port string
+	GrpcEndpoint string
+	GrpcCaFile string
+	ClientCertFile string
+	ClientKeyFile string
+}
+
+type RedisConfig struct{
+	Port string
+	User string
+	Db int
+	Password string
+	Host string
+}
+
+// RateLimiterConfig is the bucket of every caller after authentication. PeerRateLimit and
+// PeerBucketSize bound each client address before authentication, so failed attempts at
+// guessing tokens or api keys are limited as well.
+type RateLimiterConfig struct{
+	RateLimit int
+	BucketSize int
+	Overrides []string
+	PeerRateLimit int
+	PeerBucketSize int
+}
+
+// MySqlConfig is the SQL database. Driver is "mysql" (the default) or "sqlite", which
+// opens SqlitePath instead, a file or ":memory:" for a database living in the process.
+type MySqlConfig struct{
+	Port int
+    User string
+    Password string
+    Database string
+	Host string
+	Driver string
+	SqlitePath string
 }
\ No newline at end of file
@@ -0,0 +1,149 @@
+// Code generated by protoc-gen-grpc-gateway. DO NOT EDIT.
+// source: holding/holding.proto
+
+/*
+Package holding is a reverse proxy.
+
+It translates gRPC into RESTful JSON APIs.
+*/
+package holding
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/utilities"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Suppress "imported and not used" errors
+var (
+	_ codes.Code
+	_ io.Reader
+	_ status.Status
+	_ = errors.New
+	_ = runtime.String
+	_ = utilities.NewDoubleArray
+	_ = metadata.Join
+)
+
+func request_HoldingService_GetCurrentHoldings_0(ctx context.Context, marshaler runtime.Marshaler, client HoldingServiceClient, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq CurrentHoldingsRequest
+		metadata runtime.ServerMetadata
+	)
+	io.Copy(io.Discard, req.Body)
+	msg, err := client.GetCurrentHoldings(ctx, &protoReq, grpc.Header(&metadata.HeaderMD), grpc.Trailer(&metadata.TrailerMD))
+	return msg, metadata, err
+}
+
+func local_request_HoldingService_GetCurrentHoldings_0(ctx context.Context, marshaler runtime.Marshaler, server HoldingServiceServer, req *http.Request, pathParams map[string]string) (proto.Message, runtime.ServerMetadata, error) {
+	var (
+		protoReq CurrentHoldingsRequest
+		metadata runtime.ServerMetadata
+	)
+	msg, err := server.GetCurrentHoldings(ctx, &protoReq)
+	return msg, metadata, err
+}
+
+// RegisterHoldingServiceHandlerServer registers the http handlers for service HoldingService to "mux".
+// UnaryRPC     :call HoldingServiceServer directly.
+// StreamingRPC :currently unsupported pending https://github.com/grpc/grpc-go/issues/906.
+// Note that using this registration option will cause many gRPC library features to stop working. Consider using RegisterHoldingServiceHandlerFromEndpoint instead.
+// GRPC interceptors will not work for this type of registration. To use interceptors, you must use the "runtime.WithMiddlewares" option in the "runtime.NewServeMux" call.
+func RegisterHoldingServiceHandlerServer(ctx context.Context, mux *runtime.ServeMux, server HoldingServiceServer) error {
+	mux.Handle(http.MethodGet, pattern_HoldingService_GetCurrentHoldings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		var stream runtime.ServerTransportStream
+		ctx = grpc.NewContextWithServerTransportStream(ctx, &stream)
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateIncomingContext(ctx, mux, req, "/holding.HoldingService/GetCurrentHoldings", runtime.WithHTTPPathPattern("/v1/holdings"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := local_request_HoldingService_GetCurrentHoldings_0(annotatedContext, inboundMarshaler, server, req, pathParams)
+		md.HeaderMD, md.TrailerMD = metadata.Join(md.HeaderMD, stream.Header()), metadata.Join(md.TrailerMD, stream.Trailer())
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_HoldingService_GetCurrentHoldings_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
+
+	return nil
+}
+
+// RegisterHoldingServiceHandlerFromEndpoint is same as RegisterHoldingServiceHandler but
+// automatically dials to "endpoint" and closes the connection when "ctx" gets done.
+func RegisterHoldingServiceHandlerFromEndpoint(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) (err error) {
+	conn, err := grpc.NewClient(endpoint, opts...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if cerr := conn.Close(); cerr != nil {
+				grpclog.Errorf("Failed to close conn to %s: %v", endpoint, cerr)
+			}
+			return
+		}
+		go func() {
+			<-ctx.Done()
+			if cerr := conn.Close(); cerr != nil {
+				grpclog.Errorf("Failed to close conn to %s: %v", endpoint, cerr)
+			}
+		}()
+	}()
+	return RegisterHoldingServiceHandler(ctx, mux, conn)
+}
+
+// RegisterHoldingServiceHandler registers the http handlers for service HoldingService to "mux".
+// The handlers forward requests to the grpc endpoint over "conn".
+func RegisterHoldingServiceHandler(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	return RegisterHoldingServiceHandlerClient(ctx, mux, NewHoldingServiceClient(conn))
+}
+
+// RegisterHoldingServiceHandlerClient registers the http handlers for service HoldingService
+// to "mux". The handlers forward requests to the grpc endpoint over the given implementation of "HoldingServiceClient".
+// Note: the gRPC framework executes interceptors within the gRPC handler. If the passed in "HoldingServiceClient"
+// doesn't go through the normal gRPC flow (creating a gRPC client etc.) then it will be up to the passed in
+// "HoldingServiceClient" to call the correct interceptors. This client ignores the HTTP middlewares.
+func RegisterHoldingServiceHandlerClient(ctx context.Context, mux *runtime.ServeMux, client HoldingServiceClient) error {
+	mux.Handle(http.MethodGet, pattern_HoldingService_GetCurrentHoldings_0, func(w http.ResponseWriter, req *http.Request, pathParams map[string]string) {
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		inboundMarshaler, outboundMarshaler := runtime.MarshalerForRequest(mux, req)
+		annotatedContext, err := runtime.AnnotateContext(ctx, mux, req, "/holding.HoldingService/GetCurrentHoldings", runtime.WithHTTPPathPattern("/v1/holdings"))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		resp, md, err := request_HoldingService_GetCurrentHoldings_0(annotatedContext, inboundMarshaler, client, req, pathParams)
+		annotatedContext = runtime.NewServerMetadataContext(annotatedContext, md)
+		if err != nil {
+			runtime.HTTPError(annotatedContext, mux, outboundMarshaler, w, req, err)
+			return
+		}
+		forward_HoldingService_GetCurrentHoldings_0(annotatedContext, mux, outboundMarshaler, w, req, resp, mux.GetForwardResponseOptions()...)
+	})
+	return nil
+}
+
+var (
+	pattern_HoldingService_GetCurrentHoldings_0 = runtime.MustPattern(runtime.NewPattern(1, []int{2, 0, 2, 1}, []string{"v1", "holdings"}, ""))
+)
+
+var (
+	forward_HoldingService_GetCurrentHoldings_0 = runtime.ForwardResponseMessage
+)
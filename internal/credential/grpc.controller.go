@@ -0,0 +1,197 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	common "github.com/tanmaygupta069/order-service-go/generated/common"
+	credentialPb "github.com/tanmaygupta069/order-service-go/generated/credential"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+type CredentialController struct {
+	service CredentialService
+	credentialPb.UnimplementedCredentialServiceServer
+}
+
+func NewCredentialController() *CredentialController {
+	return &CredentialController{
+		service: NewCredentialService(),
+	}
+}
+
+func (s *CredentialController) RevokeToken(ctx context.Context, req *credentialPb.RevokeTokenRequest) (*credentialPb.RevokeTokenResponse, error) {
+	if req.TokenId == "" && req.UserId == "" {
+		return &credentialPb.RevokeTokenResponse{
+			Response: &common.Response{
+				Code:    http.StatusBadRequest,
+				Message: "either tokenId or userId must be set",
+			},
+		}, nil
+	}
+
+	if req.TokenId != "" {
+		var expiresAt time.Time
+		if req.ExpiresAt > 0 {
+			expiresAt = time.Unix(req.ExpiresAt, 0)
+		}
+		if err := s.service.RevokeToken(ctx, req.TokenId, expiresAt); err != nil {
+			return &credentialPb.RevokeTokenResponse{
+				Response: &common.Response{
+					Code:    http.StatusInternalServerError,
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+	if req.UserId != "" {
+		if err := s.service.RevokeUserTokens(ctx, req.UserId); err != nil {
+			return &credentialPb.RevokeTokenResponse{
+				Response: &common.Response{
+					Code:    http.StatusInternalServerError,
+					Message: err.Error(),
+				},
+			}, nil
+		}
+	}
+
+	return &credentialPb.RevokeTokenResponse{
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *CredentialController) CreateApiKey(ctx context.Context, req *credentialPb.CreateApiKeyRequest) (*credentialPb.CreateApiKeyResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return &credentialPb.CreateApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusBadRequest,
+				Message: "name can't be empty",
+			},
+		}, nil
+	}
+	if req.ExpiresInDays < 0 {
+		return &credentialPb.CreateApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusBadRequest,
+				Message: "expiresInDays can't be negative",
+			},
+		}, nil
+	}
+
+	key, record, err := s.service.CreateApiKey(ctx, strings.TrimSpace(req.Name), principal.UserId, req.Scopes, int(req.ExpiresInDays))
+	if err != nil {
+		return &credentialPb.CreateApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &credentialPb.CreateApiKeyResponse{
+		Key:    key,
+		ApiKey: toApiKey(record),
+		Response: &common.Response{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+		},
+	}, nil
+}
+
+func (s *CredentialController) ListApiKeys(ctx context.Context, req *credentialPb.ListApiKeysRequest) (*credentialPb.ListApiKeysResponse, error) {
+	res, err := s.service.ListApiKeys(ctx)
+	if err != nil {
+		return &credentialPb.ListApiKeysResponse{
+			Response: &common.Response{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	apiKeys := make([]*credentialPb.ApiKey, 0)
+	for _, record := range res {
+		apiKeys = append(apiKeys, toApiKey(record))
+	}
+
+	return &credentialPb.ListApiKeysResponse{
+		ApiKeys: apiKeys,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *CredentialController) RevokeApiKey(ctx context.Context, req *credentialPb.RevokeApiKeyRequest) (*credentialPb.RevokeApiKeyResponse, error) {
+	if req.Id == "" {
+		return &credentialPb.RevokeApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusBadRequest,
+				Message: "id can't be empty",
+			},
+		}, nil
+	}
+
+	record, err := s.service.RevokeApiKey(ctx, req.Id)
+	if errors.Is(err, ErrApiKeyNotFound) {
+		return &credentialPb.RevokeApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			},
+		}, nil
+	} else if err != nil {
+		return &credentialPb.RevokeApiKeyResponse{
+			Response: &common.Response{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			},
+		}, nil
+	}
+
+	return &credentialPb.RevokeApiKeyResponse{
+		ApiKey: toApiKey(record),
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+// toApiKey never includes the hash, the plain key is only returned by CreateApiKey.
+func toApiKey(record *mysql.ApiKeys) *credentialPb.ApiKey {
+	apiKey := &credentialPb.ApiKey{
+		Id:        record.Id,
+		Prefix:    record.Prefix,
+		Name:      record.Name,
+		CreatedBy: record.CreatedBy,
+		Scopes:    make([]string, 0),
+		CreatedAt: record.CreatedAt.Unix(),
+	}
+	if record.Scopes != "" {
+		apiKey.Scopes = strings.Split(record.Scopes, ",")
+	}
+	if record.ExpiresAt != nil {
+		apiKey.ExpiresAt = record.ExpiresAt.Unix()
+	}
+	if record.LastUsedAt != nil {
+		apiKey.LastUsedAt = record.LastUsedAt.Unix()
+	}
+	if record.RevokedAt != nil {
+		apiKey.RevokedAt = record.RevokedAt.Unix()
+	}
+	return apiKey
+}
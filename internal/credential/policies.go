@@ -0,0 +1,14 @@
+package credential
+
+import (
+	credentialPb "github.com/tanmaygupta069/order-service-go/generated/credential"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+)
+
+// MethodPolicies is the access policy of every CredentialService method, enforced by the auth interceptor.
+var MethodPolicies = map[string]string{
+	credentialPb.CredentialService_RevokeToken_FullMethodName:  auth.POLICY_ADMIN,
+	credentialPb.CredentialService_CreateApiKey_FullMethodName: auth.POLICY_ADMIN,
+	credentialPb.CredentialService_ListApiKeys_FullMethodName:  auth.POLICY_ADMIN,
+	credentialPb.CredentialService_RevokeApiKey_FullMethodName: auth.POLICY_ADMIN,
+}
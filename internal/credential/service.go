@@ -0,0 +1,76 @@
+package credential
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"gorm.io/gorm"
+)
+
+var ErrApiKeyNotFound = errors.New("api key not found")
+
+// allowedScopes are the scopes an api key may be created with.
+var allowedScopes = map[string]bool{
+	auth.ROLE_ADMIN:        true,
+	auth.ROLE_OPERATOR:     true,
+	auth.SCOPE_ACT_AS_USER: true,
+}
+
+type CredentialService interface {
+	RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userId string) error
+	CreateApiKey(ctx context.Context, name string, createdBy string, scopes []string, expiresInDays int) (string, *mysql.ApiKeys, error)
+	ListApiKeys(ctx context.Context) ([]*mysql.ApiKeys, error)
+	RevokeApiKey(ctx context.Context, id string) (*mysql.ApiKeys, error)
+}
+
+type CredentialServiceImp struct {
+	revocations auth.RevocationStore
+	apiKeys     auth.ApiKeyStore
+}
+
+func NewCredentialService() CredentialService {
+	return &CredentialServiceImp{
+		revocations: auth.NewRevocationStore(),
+		apiKeys:     auth.NewApiKeyStore(),
+	}
+}
+
+func (r *CredentialServiceImp) RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	return r.revocations.RevokeToken(ctx, tokenId, expiresAt)
+}
+
+// RevokeUserTokens cuts off every token the user holds right now, new logins keep working.
+func (r *CredentialServiceImp) RevokeUserTokens(ctx context.Context, userId string) error {
+	return r.revocations.RevokeUserTokens(ctx, userId, time.Now())
+}
+
+func (r *CredentialServiceImp) CreateApiKey(ctx context.Context, name string, createdBy string, scopes []string, expiresInDays int) (string, *mysql.ApiKeys, error) {
+	for _, scope := range scopes {
+		if !allowedScopes[scope] {
+			return "", nil, fmt.Errorf("unknown scope %s", scope)
+		}
+	}
+	var expiresAt *time.Time
+	if expiresInDays > 0 {
+		expiry := time.Now().AddDate(0, 0, expiresInDays)
+		expiresAt = &expiry
+	}
+	return r.apiKeys.CreateKey(ctx, name, createdBy, scopes, expiresAt)
+}
+
+func (r *CredentialServiceImp) ListApiKeys(ctx context.Context) ([]*mysql.ApiKeys, error) {
+	return r.apiKeys.ListKeys(ctx)
+}
+
+func (r *CredentialServiceImp) RevokeApiKey(ctx context.Context, id string) (*mysql.ApiKeys, error) {
+	key, err := r.apiKeys.RevokeKey(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrApiKeyNotFound
+	}
+	return key, err
+}
@@ -0,0 +1,13 @@
+package holding
+
+import "github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+
+const (
+	REASON_HOLDING_NOT_FOUND     = "HOLDING_NOT_FOUND"
+	REASON_INSUFFICIENT_HOLDINGS = "INSUFFICIENT_HOLDINGS"
+)
+
+var (
+	ErrHoldingNotFound      = apperror.NotFound(REASON_HOLDING_NOT_FOUND, "no holding found for symbol")
+	ErrInsufficientHoldings = apperror.FailedPrecondition(REASON_INSUFFICIENT_HOLDINGS, "can't sell more than your current holdings")
+)
@@ -0,0 +1,11 @@
+package holding
+
+import (
+	holdingPb "github.com/tanmaygupta069/order-service-go/generated/holding"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+)
+
+// MethodPolicies is the access policy of every HoldingService method, enforced by the auth interceptor.
+var MethodPolicies = map[string]string{
+	holdingPb.HoldingService_GetCurrentHoldings_FullMethodName: auth.POLICY_USER,
+}
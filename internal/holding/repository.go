@@ -1,65 +1,81 @@
-package holding
-
-import (
-	"errors"
-
-	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
-	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
-	"gorm.io/gorm"
-)
-
-type HoldingRepository interface {
-	UpdateHoldings(holding *mysql.Holdings) error
-	GetHolding(holding *mysql.Holdings)(*mysql.Holdings,error)
-	InsertHolding(holding *mysql.Holdings)(error)
-	GetHoldings(holding *mysql.Holdings)([]*mysql.Holdings,error)
-}
-
-type HoldingRepositoryImp struct{
-	mysql *mysql.SqlServiceImplementation[mysql.Holdings]
-	redis Redis.RedisInterface
-}
-
-func NewHoldingRepository()HoldingRepository{
-	return &HoldingRepositoryImp{
-		mysql : mysql.NewSqlClient[mysql.Holdings](),
-		redis:  Redis.NewRedisClient(),
-	}
-}
-
-func (db *HoldingRepositoryImp)UpdateHoldings(holding *mysql.Holdings)error{
-	return db.mysql.Update(holding)
-}
-
-func (db *HoldingRepositoryImp)GetHolding(holding *mysql.Holdings)(*mysql.Holdings,error){
-	existingHolding, err := db.mysql.GetOne(map[string]interface{}{
-		"symbol":holding.Symbol,
-		"user_id":holding.UserId,
-	})
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil,err
-		}
-	}
-	return existingHolding,nil
-}
-
-func (db *HoldingRepositoryImp)InsertHolding(holding *mysql.Holdings)(error){
-	return db.mysql.Insert(holding)
-}
-
-func (db *HoldingRepositoryImp)GetHoldings(holding *mysql.Holdings)([]*mysql.Holdings,error){
-	holdings,err:=db.mysql.GetAll(map[string]interface{}{
-		"user_id":holding.UserId,
-	})
-	if err!=nil{
-		return nil,err
-	}
-	result := make([]*mysql.Holdings, len(holdings))
-	for i := range holdings {
-		result[i] = &holdings[i]
-	}
-
-	return result,nil
-
+package holding
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"gorm.io/gorm"
+)
+
+type HoldingRepository interface {
+	UpdateHoldings(ctx context.Context, holding *mysql.Holdings) error
+	GetHolding(ctx context.Context, holding *mysql.Holdings)(*mysql.Holdings,error)
+	InsertHolding(ctx context.Context, holding *mysql.Holdings)(error)
+	GetHoldings(ctx context.Context, holding *mysql.Holdings)([]*mysql.Holdings,error)
+}
+
+type HoldingRepositoryImp struct{
+	mysql *mysql.SqlServiceImplementation[mysql.Holdings]
+	redis Redis.RedisInterface
+}
+
+var cfg, _ = config.GetConfig()
+
+// NewHoldingRepository is backed by MySQL, or by the shared in-memory repository when
+// REPOSITORY_BACKEND is memory.
+func NewHoldingRepository()HoldingRepository{
+	if cfg != nil && cfg.StorageConfig.RepositoryBackend == config.BACKEND_MEMORY {
+		return getMemoryHoldingRepository()
+	}
+	return &HoldingRepositoryImp{
+		mysql : mysql.NewSqlClient[mysql.Holdings](),
+		redis:  Redis.NewRedisClient(),
+	}
+}
+
+// NewSqlHoldingRepository returns a repository of its own on the database d instead of
+// the shared connection.
+func NewSqlHoldingRepository(d *gorm.DB) *HoldingRepositoryImp {
+	return &HoldingRepositoryImp{
+		mysql: mysql.NewSqlClientFor[mysql.Holdings](d),
+	}
+}
+
+func (db *HoldingRepositoryImp)UpdateHoldings(ctx context.Context, holding *mysql.Holdings)error{
+	return db.mysql.Update(ctx, holding)
+}
+
+func (db *HoldingRepositoryImp)GetHolding(ctx context.Context, holding *mysql.Holdings)(*mysql.Holdings,error){
+	existingHolding, err := db.mysql.GetOne(ctx, mysql.Where(
+		mysql.Eq("user_id",holding.UserId),
+		mysql.Eq("symbol",holding.Symbol),
+	))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil,ErrHoldingNotFound.Withf("no holding of %s found",holding.Symbol).Wrap(err)
+	}
+	if err != nil {
+		return nil,err
+	}
+	return existingHolding,nil
+}
+
+func (db *HoldingRepositoryImp)InsertHolding(ctx context.Context, holding *mysql.Holdings)(error){
+	return db.mysql.Insert(ctx, holding)
+}
+
+func (db *HoldingRepositoryImp)GetHoldings(ctx context.Context, holding *mysql.Holdings)([]*mysql.Holdings,error){
+	holdings,err:=db.mysql.GetAll(ctx, mysql.Where(mysql.Eq("user_id",holding.UserId)).OrderBy("symbol",false))
+	if err!=nil{
+		return nil,err
+	}
+	result := make([]*mysql.Holdings, len(holdings))
+	for i := range holdings {
+		result[i] = &holdings[i]
+	}
+
+	return result,nil
+
 }
\ No newline at end of file
@@ -0,0 +1,88 @@
+package holding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"gorm.io/gorm"
+)
+
+type holdingKey struct {
+	userId string
+	symbol string
+}
+
+// MemoryHoldingRepository keeps holdings in the process. It hands out copies, so like the
+// SQL repository a caller only changes what is stored by calling it.
+type MemoryHoldingRepository struct {
+	mu       sync.RWMutex
+	holdings map[holdingKey]mysql.Holdings
+}
+
+var sharedHoldingRepository *MemoryHoldingRepository
+
+var memoryOnce sync.Once
+
+// NewMemoryHoldingRepository returns an empty repository of its own, NewHoldingRepository
+// hands out one shared repository when REPOSITORY_BACKEND is memory.
+func NewMemoryHoldingRepository() *MemoryHoldingRepository {
+	return &MemoryHoldingRepository{
+		holdings: make(map[holdingKey]mysql.Holdings),
+	}
+}
+
+func getMemoryHoldingRepository() *MemoryHoldingRepository {
+	memoryOnce.Do(func() {
+		sharedHoldingRepository = NewMemoryHoldingRepository()
+	})
+	return sharedHoldingRepository
+}
+
+// UpdateHoldings saves holding whether or not it exists yet, like gorm's Save.
+func (db *MemoryHoldingRepository) UpdateHoldings(ctx context.Context, holding *mysql.Holdings) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.holdings[holdingKey{holding.UserId, holding.Symbol}] = *holding
+	return nil
+}
+
+func (db *MemoryHoldingRepository) GetHolding(ctx context.Context, holding *mysql.Holdings) (*mysql.Holdings, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	existing, ok := db.holdings[holdingKey{holding.UserId, holding.Symbol}]
+	if !ok {
+		return nil, ErrHoldingNotFound.Withf("no holding of %s found", holding.Symbol).Wrap(gorm.ErrRecordNotFound)
+	}
+	return &existing, nil
+}
+
+func (db *MemoryHoldingRepository) InsertHolding(ctx context.Context, holding *mysql.Holdings) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	key := holdingKey{holding.UserId, holding.Symbol}
+	if _, ok := db.holdings[key]; ok {
+		return fmt.Errorf("holding of %s for %s already exists : %w", holding.Symbol, holding.UserId, gorm.ErrDuplicatedKey)
+	}
+	db.holdings[key] = *holding
+	return nil
+}
+
+// GetHoldings returns the user's holdings by symbol.
+func (db *MemoryHoldingRepository) GetHoldings(ctx context.Context, holding *mysql.Holdings) ([]*mysql.Holdings, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make([]*mysql.Holdings, 0)
+	for key, existing := range db.holdings {
+		if key.userId == holding.UserId {
+			existing := existing
+			result = append(result, &existing)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package holding
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"gorm.io/gorm"
+)
+
+// forEachHoldingRepository runs test against an empty repository of every backend, they
+// have to behave the same for the service.
+func forEachHoldingRepository(t *testing.T, test func(t *testing.T, repo HoldingRepository)) {
+	backends := map[string]func(t *testing.T) HoldingRepository{
+		config.BACKEND_MEMORY: func(t *testing.T) HoldingRepository {
+			return NewMemoryHoldingRepository()
+		},
+		config.BACKEND_SQL: func(t *testing.T) HoldingRepository {
+			d, err := mysql.OpenDatabase(context.Background(), config.MySqlConfig{
+				Driver:     config.DRIVER_SQLITE,
+				SqlitePath: ":memory:",
+			})
+			if err != nil {
+				t.Fatalf("error opening test database : %v", err)
+			}
+			t.Cleanup(func() {
+				if sqlDB, err := d.DB(); err == nil {
+					sqlDB.Close()
+				}
+			})
+			return NewSqlHoldingRepository(d)
+		},
+	}
+	for name, newRepo := range backends {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepo(t))
+		})
+	}
+}
+
+func testHolding(userId string, symbol string, quantity int32, total string) *mysql.Holdings {
+	return &mysql.Holdings{
+		UserId:     userId,
+		Symbol:     symbol,
+		Quantity:   quantity,
+		TotalPrice: decimal.RequireFromString(total),
+	}
+}
+
+func insertTestHolding(t *testing.T, repo HoldingRepository, holding *mysql.Holdings) {
+	t.Helper()
+	if err := repo.InsertHolding(context.Background(), holding); err != nil {
+		t.Fatalf("InsertHolding(%s, %s) error = %v", holding.UserId, holding.Symbol, err)
+	}
+}
+
+func TestHoldingRepositoryInsertAndGet(t *testing.T) {
+	forEachHoldingRepository(t, func(t *testing.T, repo HoldingRepository) {
+		ctx := context.Background()
+		if _, err := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u1", Symbol: "AAPL"}); !errors.Is(err, ErrHoldingNotFound) {
+			t.Errorf("GetHolding() of a missing holding error = %v, want ErrHoldingNotFound", err)
+		}
+
+		insertTestHolding(t, repo, testHolding("u1", "AAPL", 10, "1012.5"))
+		got, err := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u1", Symbol: "AAPL"})
+		if err != nil {
+			t.Fatalf("GetHolding() error = %v", err)
+		}
+		if got.Quantity != 10 || !got.TotalPrice.Equal(decimal.RequireFromString("1012.5")) {
+			t.Errorf("GetHolding() = %d for %s, want 10 for 1012.5", got.Quantity, got.TotalPrice)
+		}
+		if _, err := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u2", Symbol: "AAPL"}); !errors.Is(err, ErrHoldingNotFound) {
+			t.Errorf("GetHolding() of another user's symbol error = %v, want ErrHoldingNotFound", err)
+		}
+
+		if err := repo.InsertHolding(ctx, testHolding("u1", "AAPL", 1, "1")); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Errorf("InsertHolding() of an existing holding error = %v, want gorm.ErrDuplicatedKey", err)
+		}
+	})
+}
+
+func TestHoldingRepositoryUpdate(t *testing.T) {
+	forEachHoldingRepository(t, func(t *testing.T, repo HoldingRepository) {
+		ctx := context.Background()
+		insertTestHolding(t, repo, testHolding("u1", "AAPL", 10, "1000"))
+		insertTestHolding(t, repo, testHolding("u2", "AAPL", 5, "500"))
+
+		if err := repo.UpdateHoldings(ctx, testHolding("u1", "AAPL", 4, "400")); err != nil {
+			t.Fatalf("UpdateHoldings() error = %v", err)
+		}
+		got, err := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u1", Symbol: "AAPL"})
+		if err != nil {
+			t.Fatalf("GetHolding() error = %v", err)
+		}
+		if got.Quantity != 4 || !got.TotalPrice.Equal(decimal.NewFromInt(400)) {
+			t.Errorf("updated holding = %d for %s, want 4 for 400", got.Quantity, got.TotalPrice)
+		}
+		if other, _ := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u2", Symbol: "AAPL"}); other == nil || other.Quantity != 5 {
+			t.Errorf("UpdateHoldings() changed another user's holding to %+v", other)
+		}
+
+		// like gorm's Save a missing holding is stored
+		if err := repo.UpdateHoldings(ctx, testHolding("u1", "MSFT", 2, "600")); err != nil {
+			t.Fatalf("UpdateHoldings() of a new holding error = %v", err)
+		}
+		if got, err := repo.GetHolding(ctx, &mysql.Holdings{UserId: "u1", Symbol: "MSFT"}); err != nil || got.Quantity != 2 {
+			t.Errorf("GetHolding() after UpdateHoldings() of a new holding = %+v, %v", got, err)
+		}
+	})
+}
+
+func TestHoldingRepositoryGetHoldings(t *testing.T) {
+	forEachHoldingRepository(t, func(t *testing.T, repo HoldingRepository) {
+		insertTestHolding(t, repo, testHolding("u1", "MSFT", 1, "300"))
+		insertTestHolding(t, repo, testHolding("u1", "AAPL", 2, "200"))
+		insertTestHolding(t, repo, testHolding("u2", "GOOG", 3, "100"))
+		insertTestHolding(t, repo, testHolding("u1", "GOOG", 4, "400"))
+
+		holdings, err := repo.GetHoldings(context.Background(), &mysql.Holdings{UserId: "u1"})
+		if err != nil {
+			t.Fatalf("GetHoldings() error = %v", err)
+		}
+		got := make([]string, 0, len(holdings))
+		for _, holding := range holdings {
+			got = append(got, holding.Symbol)
+		}
+		if want := []string{"AAPL", "GOOG", "MSFT"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetHoldings() = %v, want %v", got, want)
+		}
+		if holdings, _ := repo.GetHoldings(context.Background(), &mysql.Holdings{UserId: "u3"}); len(holdings) != 0 {
+			t.Errorf("GetHoldings() of a user without holdings = %d holdings, want none", len(holdings))
+		}
+	})
+}
@@ -1,66 +1,68 @@
-package holding
-
-import (
-	"fmt"
-	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
-	"google.golang.org/grpc/metadata"
-)
-
-type HoldingService interface {
-	UpdateHoldings(holding *mysql.Holdings, orderType string) error
-	GetHolding(userId string, symbol string) (*mysql.Holdings, error)
-	GetHoldings(userId string)([]*mysql.Holdings,error)
-	GetTokenFromMetadata(md metadata.MD) (string, error)
-}
-
-type HoldingServiceImp struct {
-	repo HoldingRepository
-}
-
-func NewHoldingService() HoldingService {
-	return &HoldingServiceImp{
-		repo: NewHoldingRepository(),
-	}
-}
-
-func (r *HoldingServiceImp) UpdateHoldings(holding *mysql.Holdings, orderType string) error {
-	exsistingHolding, err := r.repo.GetHolding(holding)
-	if err != nil {
-		return r.repo.InsertHolding(holding)
-	}
-	if orderType == "BUY" {
-		holding.Quantity += exsistingHolding.Quantity
-		holding.TotalPrice += exsistingHolding.TotalPrice
-	} else if orderType == "SELL" {
-		if exsistingHolding.Quantity < holding.Quantity {
-			return fmt.Errorf("can't sell,number of holding for %s is smaller than holdings to be sold", holding.Symbol)
-		}
-		exsistingHolding.Quantity -= holding.Quantity
-		exsistingHolding.TotalPrice -= holding.TotalPrice
-	}
-	return r.repo.UpdateHoldings(holding)
-}
-
-func (r *HoldingServiceImp) GetHolding(userId string, symbol string) (*mysql.Holdings, error) {
-	holding, err := r.repo.GetHolding(&mysql.Holdings{
-		UserId: userId,
-		Symbol: symbol,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return holding, nil
-}
-
-func (r *HoldingServiceImp) GetTokenFromMetadata(md metadata.MD) (string, error) {
-	token := md.Get("Authorization")
-	if len(token) == 0 {
-		return "", fmt.Errorf("no token found")
-	}
-	return token[0], nil
-}
-func (r *HoldingServiceImp)GetHoldings(userId string)([]*mysql.Holdings,error){
-	return r.repo.GetHoldings(&mysql.Holdings{
-		UserId: userId,
-	})
+package holding
+
+import (
+	"context"
+	"errors"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+type HoldingService interface {
+	UpdateHoldings(ctx context.Context, holding *mysql.Holdings, orderType string) error
+	GetHolding(ctx context.Context, userId string, symbol string) (*mysql.Holdings, error)
+	GetHoldings(ctx context.Context, userId string)([]*mysql.Holdings,error)
+}
+
+type HoldingServiceImp struct {
+	repo HoldingRepository
+}
+
+func NewHoldingService() HoldingService {
+	return &HoldingServiceImp{
+		repo: NewHoldingRepository(),
+	}
+}
+
+// NewHoldingServiceWithRepository keeps the holdings in repo instead of the configured backend.
+func NewHoldingServiceWithRepository(repo HoldingRepository) HoldingService {
+	return &HoldingServiceImp{
+		repo: repo,
+	}
+}
+
+func (r *HoldingServiceImp) UpdateHoldings(ctx context.Context, holding *mysql.Holdings, orderType string) error {
+	existingHolding, err := r.repo.GetHolding(ctx, holding)
+	if errors.Is(err, ErrHoldingNotFound) {
+		return r.repo.InsertHolding(ctx, holding)
+	}
+	if err != nil {
+		return err
+	}
+	if orderType == "BUY" {
+		existingHolding.Quantity += holding.Quantity
+		existingHolding.TotalPrice = existingHolding.TotalPrice.Add(holding.TotalPrice)
+	} else if orderType == "SELL" {
+		if existingHolding.Quantity < holding.Quantity {
+			return ErrInsufficientHoldings.Withf("can't sell,number of holding for %s is smaller than holdings to be sold", holding.Symbol)
+		}
+		existingHolding.Quantity -= holding.Quantity
+		existingHolding.TotalPrice = existingHolding.TotalPrice.Sub(holding.TotalPrice)
+	}
+	return r.repo.UpdateHoldings(ctx, existingHolding)
+}
+
+func (r *HoldingServiceImp) GetHolding(ctx context.Context, userId string, symbol string) (*mysql.Holdings, error) {
+	holding, err := r.repo.GetHolding(ctx, &mysql.Holdings{
+		UserId: userId,
+		Symbol: symbol,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holding, nil
+}
+
+func (r *HoldingServiceImp)GetHoldings(ctx context.Context, userId string)([]*mysql.Holdings,error){
+	return r.repo.GetHoldings(ctx, &mysql.Holdings{
+		UserId: userId,
+	})
 }
\ No newline at end of file
@@ -0,0 +1,324 @@
+package market
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+var cfg, _ = config.GetConfig()
+
+const (
+	SESSION_CLOSED      = "closed"
+	SESSION_PRE_MARKET  = "pre_market"
+	SESSION_REGULAR     = "regular"
+	SESSION_AFTER_HOURS = "after_hours"
+)
+
+const dateLayout = "2006-01-02"
+
+// lookAheadDays bounds the search for the next open so a misconfigured calendar
+// with no trading days can't loop forever.
+const lookAheadDays = 14
+
+var defaultCalendar = ExchangeCalendar{
+	Exchange:    "DEFAULT",
+	Timezone:    "America/New_York",
+	PreMarket:   &SessionHours{Open: "04:00", Close: "09:30"},
+	Regular:     SessionHours{Open: "09:30", Close: "16:00"},
+	AfterHours:  &SessionHours{Open: "16:00", Close: "20:00"},
+	TradingDays: []string{"Mon", "Tue", "Wed", "Thu", "Fri"},
+}
+
+type TradingCalendar interface {
+	Session(exchange string, t time.Time) string
+	Status(exchange string, t time.Time) *MarketStatus
+	CanTrade(exchange string, t time.Time, preMarket bool, afterHours bool) bool
+	SessionEnd(exchange string, t time.Time, afterHours bool) time.Time
+}
+
+type TradingCalendarImp struct {
+	schedules map[string]*schedule
+	fallback  *schedule
+}
+
+// window is a session expressed in minutes after local midnight.
+type window struct {
+	open  int
+	close int
+}
+
+type schedule struct {
+	exchange    string
+	location    *time.Location
+	preMarket   *window
+	regular     window
+	afterHours  *window
+	tradingDays map[time.Weekday]bool
+	holidays    map[string]bool
+	halfDays    map[string]int
+}
+
+func NewTradingCalendar() TradingCalendar {
+	calendar, err := LoadTradingCalendar(cfg.MarketConfig.CalendarFile)
+	if err != nil {
+		fmt.Printf("error loading trading calendar, falling back to default : %v\n", err)
+		calendar, _ = newTradingCalendar([]ExchangeCalendar{defaultCalendar})
+	}
+	return calendar
+}
+
+// LoadTradingCalendar reads a JSON list of exchange calendars. The first entry is used
+// for any exchange that isn't listed. An empty path gives the default US equities calendar.
+func LoadTradingCalendar(path string) (*TradingCalendarImp, error) {
+	if path == "" {
+		return newTradingCalendar([]ExchangeCalendar{defaultCalendar})
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading calendar file : %v", err)
+	}
+	var calendars []ExchangeCalendar
+	if err = json.Unmarshal(data, &calendars); err != nil {
+		return nil, fmt.Errorf("error parsing calendar file : %v", err)
+	}
+	return newTradingCalendar(calendars)
+}
+
+func newTradingCalendar(calendars []ExchangeCalendar) (*TradingCalendarImp, error) {
+	if len(calendars) == 0 {
+		return nil, fmt.Errorf("calendar has no exchanges")
+	}
+	res := &TradingCalendarImp{
+		schedules: make(map[string]*schedule),
+	}
+	for _, calendar := range calendars {
+		s, err := parseSchedule(calendar)
+		if err != nil {
+			return nil, fmt.Errorf("exchange %s : %v", calendar.Exchange, err)
+		}
+		if res.fallback == nil {
+			res.fallback = s
+		}
+		res.schedules[s.exchange] = s
+	}
+	return res, nil
+}
+
+func (c *TradingCalendarImp) scheduleFor(exchange string) *schedule {
+	if s, ok := c.schedules[strings.ToUpper(exchange)]; ok {
+		return s
+	}
+	return c.fallback
+}
+
+func (c *TradingCalendarImp) Session(exchange string, t time.Time) string {
+	return c.scheduleFor(exchange).session(t)
+}
+
+func (c *TradingCalendarImp) CanTrade(exchange string, t time.Time, preMarket bool, afterHours bool) bool {
+	switch c.Session(exchange, t) {
+	case SESSION_REGULAR:
+		return true
+	case SESSION_PRE_MARKET:
+		return preMarket
+	case SESSION_AFTER_HOURS:
+		return afterHours
+	}
+	return false
+}
+
+// SessionEnd is when the trading day a day order placed at t is good for ends. That is
+// the regular close, or the after hours close when afterHours, of t's trading day if it
+// hasn't ended yet and else of the next trading day. It is zero when there is no trading
+// day within lookAheadDays.
+func (c *TradingCalendarImp) SessionEnd(exchange string, t time.Time, afterHours bool) time.Time {
+	return c.scheduleFor(exchange).sessionEnd(t, afterHours)
+}
+
+func (c *TradingCalendarImp) Status(exchange string, t time.Time) *MarketStatus {
+	s := c.scheduleFor(exchange)
+	session := s.session(t)
+	status := &MarketStatus{
+		Exchange: s.exchange,
+		Timezone: s.location.String(),
+		Session:  session,
+		IsOpen:   session == SESSION_REGULAR,
+	}
+	if status.IsOpen {
+		_, status.NextClose, _ = s.regularWindow(t)
+	}
+	status.NextOpen = s.nextOpen(t)
+	if !status.IsOpen && !status.NextOpen.IsZero() {
+		_, status.NextClose, _ = s.regularWindow(status.NextOpen)
+	}
+	return status
+}
+
+func (s *schedule) isTradingDay(local time.Time) bool {
+	return s.tradingDays[local.Weekday()] && !s.holidays[local.Format(dateLayout)]
+}
+
+// regularWindow returns the regular session open and close on the local day of t.
+func (s *schedule) regularWindow(t time.Time) (time.Time, time.Time, bool) {
+	local := t.In(s.location)
+	if !s.isTradingDay(local) {
+		return time.Time{}, time.Time{}, false
+	}
+	closeMinute := s.regular.close
+	if halfDayClose, ok := s.halfDays[local.Format(dateLayout)]; ok {
+		closeMinute = halfDayClose
+	}
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+	return midnight.Add(time.Duration(s.regular.open) * time.Minute), midnight.Add(time.Duration(closeMinute) * time.Minute), true
+}
+
+func (s *schedule) session(t time.Time) string {
+	local := t.In(s.location)
+	if !s.isTradingDay(local) {
+		return SESSION_CLOSED
+	}
+	minute := local.Hour()*60 + local.Minute()
+	regular := s.regular
+	afterHours := s.afterHours
+	if halfDayClose, ok := s.halfDays[local.Format(dateLayout)]; ok {
+		regular.close = halfDayClose
+		afterHours = nil
+	}
+	switch {
+	case regular.contains(minute):
+		return SESSION_REGULAR
+	case s.preMarket != nil && s.preMarket.contains(minute):
+		return SESSION_PRE_MARKET
+	case afterHours != nil && afterHours.contains(minute):
+		return SESSION_AFTER_HOURS
+	}
+	return SESSION_CLOSED
+}
+
+// nextOpen finds the first regular session open strictly after t.
+func (s *schedule) nextOpen(t time.Time) time.Time {
+	local := t.In(s.location)
+	for i := 0; i <= lookAheadDays; i++ {
+		open, _, ok := s.regularWindow(local.AddDate(0, 0, i))
+		if ok && open.After(t) {
+			return open
+		}
+	}
+	return time.Time{}
+}
+
+func (s *schedule) sessionEnd(t time.Time, afterHours bool) time.Time {
+	local := t.In(s.location)
+	for i := 0; i <= lookAheadDays; i++ {
+		day := local.AddDate(0, 0, i)
+		_, closeAt, ok := s.regularWindow(day)
+		if !ok {
+			continue
+		}
+		// half days have no after hours session
+		if _, halfDay := s.halfDays[day.Format(dateLayout)]; afterHours && s.afterHours != nil && !halfDay {
+			midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.location)
+			closeAt = midnight.Add(time.Duration(s.afterHours.close) * time.Minute)
+		}
+		if closeAt.After(t) {
+			return closeAt
+		}
+	}
+	return time.Time{}
+}
+
+func (w window) contains(minute int) bool {
+	return minute >= w.open && minute < w.close
+}
+
+func parseSchedule(calendar ExchangeCalendar) (*schedule, error) {
+	location, err := time.LoadLocation(calendar.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %s : %v", calendar.Timezone, err)
+	}
+	regular, err := parseWindow(calendar.Regular)
+	if err != nil {
+		return nil, fmt.Errorf("regular session : %v", err)
+	}
+	s := &schedule{
+		exchange:    strings.ToUpper(calendar.Exchange),
+		location:    location,
+		regular:     *regular,
+		tradingDays: make(map[time.Weekday]bool),
+		holidays:    make(map[string]bool),
+		halfDays:    make(map[string]int),
+	}
+	if calendar.PreMarket != nil {
+		if s.preMarket, err = parseWindow(*calendar.PreMarket); err != nil {
+			return nil, fmt.Errorf("pre market session : %v", err)
+		}
+	}
+	if calendar.AfterHours != nil {
+		if s.afterHours, err = parseWindow(*calendar.AfterHours); err != nil {
+			return nil, fmt.Errorf("after hours session : %v", err)
+		}
+	}
+	for _, day := range calendar.TradingDays {
+		weekday, ok := weekdays[strings.ToLower(day)]
+		if !ok {
+			return nil, fmt.Errorf("invalid trading day %s", day)
+		}
+		s.tradingDays[weekday] = true
+	}
+	for _, day := range calendar.Holidays {
+		if _, err := time.Parse(dateLayout, day); err != nil {
+			return nil, fmt.Errorf("invalid holiday %s", day)
+		}
+		s.holidays[day] = true
+	}
+	for day, closeAt := range calendar.HalfDays {
+		if _, err := time.Parse(dateLayout, day); err != nil {
+			return nil, fmt.Errorf("invalid half day %s", day)
+		}
+		minute, err := parseMinute(closeAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid half day close for %s : %v", day, err)
+		}
+		s.halfDays[day] = minute
+	}
+	return s, nil
+}
+
+func parseWindow(hours SessionHours) (*window, error) {
+	open, err := parseMinute(hours.Open)
+	if err != nil {
+		return nil, err
+	}
+	closeAt, err := parseMinute(hours.Close)
+	if err != nil {
+		return nil, err
+	}
+	if closeAt <= open {
+		return nil, fmt.Errorf("close %s must be after open %s", hours.Close, hours.Open)
+	}
+	return &window{open: open, close: closeAt}, nil
+}
+
+func parseMinute(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
@@ -0,0 +1,40 @@
+package market
+
+import "time"
+
+// SessionHours is an "HH:MM" open and close pair in the exchange time zone.
+type SessionHours struct {
+	Open  string `json:"open"`
+	Close string `json:"close"`
+}
+
+// ExchangeCalendar is the on-disk description of one exchange's trading week.
+type ExchangeCalendar struct {
+	Exchange    string            `json:"exchange"`
+	Timezone    string            `json:"timezone"`
+	PreMarket   *SessionHours     `json:"preMarket"`
+	Regular     SessionHours      `json:"regular"`
+	AfterHours  *SessionHours     `json:"afterHours"`
+	TradingDays []string          `json:"tradingDays"`
+	Holidays    []string          `json:"holidays"`
+	HalfDays    map[string]string `json:"halfDays"`
+}
+
+type MarketStatus struct {
+	Exchange  string
+	Timezone  string
+	Session   string
+	IsOpen    bool
+	NextOpen  time.Time
+	NextClose time.Time
+}
+
+// Halt is a trading halt on one symbol, or on the whole market when Symbol is MARKET_WIDE.
+type Halt struct {
+	Symbol    string `json:"symbol"`
+	Reason    string `json:"reason"`
+	Source    string `json:"source"`
+	HaltedBy  string `json:"haltedBy"`
+	HaltedAt  int64  `json:"haltedAt"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
@@ -0,0 +1,101 @@
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+)
+
+const (
+	haltKeyPrefix      = "halt:"
+	referenceKeyPrefix = "circuit:reference:"
+)
+
+type HaltRepository interface {
+	SaveHalt(ctx context.Context, halt *Halt, exp int) error
+	GetHalt(ctx context.Context, symbol string) (*Halt, error)
+	DeleteHalt(ctx context.Context, symbol string) (bool, error)
+	ListHalts(ctx context.Context) ([]*Halt, error)
+	GetReferencePrice(ctx context.Context, symbol string) (float64, bool)
+	SetReferencePrice(ctx context.Context, symbol string, price float64, exp int) error
+}
+
+type HaltRepositoryImp struct {
+	redis Redis.RedisInterface
+}
+
+func NewHaltRepository() HaltRepository {
+	return &HaltRepositoryImp{
+		redis: Redis.NewRedisClient(),
+	}
+}
+
+func (db *HaltRepositoryImp) SaveHalt(ctx context.Context, halt *Halt, exp int) error {
+	data, err := json.Marshal(halt)
+	if err != nil {
+		return err
+	}
+	return db.redis.Set(ctx, haltKeyPrefix+halt.Symbol, string(data), exp)
+}
+
+// GetHalt returns nil without an error when the symbol isn't halted. When redis can't be
+// asked it returns the error, a halt nobody could read must not let orders through.
+func (db *HaltRepositoryImp) GetHalt(ctx context.Context, symbol string) (*Halt, error) {
+	val, err := db.redis.Get(ctx, haltKeyPrefix+symbol)
+	if errors.Is(err, Redis.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading halt for %s : %w", symbol, err)
+	}
+	var halt Halt
+	if err = json.Unmarshal([]byte(val), &halt); err != nil {
+		return nil, fmt.Errorf("error reading halt for %s : %v", symbol, err)
+	}
+	return &halt, nil
+}
+
+func (db *HaltRepositoryImp) DeleteHalt(ctx context.Context, symbol string) (bool, error) {
+	deleted, err := db.redis.Delete(ctx, haltKeyPrefix+symbol)
+	return deleted > 0, err
+}
+
+func (db *HaltRepositoryImp) ListHalts(ctx context.Context) ([]*Halt, error) {
+	keys, err := db.redis.Keys(ctx, haltKeyPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	halts := make([]*Halt, 0)
+	for _, key := range keys {
+		halt, err := db.GetHalt(ctx, strings.TrimPrefix(key, haltKeyPrefix))
+		if err != nil {
+			return nil, err
+		}
+		// the key may have expired between the scan and the read
+		if halt != nil {
+			halts = append(halts, halt)
+		}
+	}
+	return halts, nil
+}
+
+func (db *HaltRepositoryImp) GetReferencePrice(ctx context.Context, symbol string) (float64, bool) {
+	val, err := db.redis.Get(ctx, referenceKeyPrefix+symbol)
+	if err != nil {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+func (db *HaltRepositoryImp) SetReferencePrice(ctx context.Context, symbol string, price float64, exp int) error {
+	return db.redis.Set(ctx, referenceKeyPrefix+symbol, strconv.FormatFloat(price, 'f', -1, 64), exp)
+}
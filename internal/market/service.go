@@ -0,0 +1,115 @@
+package market
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const MARKET_WIDE = "*"
+
+const (
+	HALT_SOURCE_MANUAL          = "manual"
+	HALT_SOURCE_CIRCUIT_BREAKER = "circuit_breaker"
+)
+
+type HaltService interface {
+	Halt(ctx context.Context, symbol string, reason string, haltedBy string, minutes int) (*Halt, error)
+	Resume(ctx context.Context, symbol string) (bool, error)
+	GetActiveHalt(ctx context.Context, symbol string) (*Halt, error)
+	ListHalts(ctx context.Context) ([]*Halt, error)
+	ObservePrice(ctx context.Context, symbol string, price float64) (*Halt, error)
+}
+
+type HaltServiceImp struct {
+	repo HaltRepository
+}
+
+func NewHaltService() HaltService {
+	return &HaltServiceImp{
+		repo: NewHaltRepository(),
+	}
+}
+
+// Halt stops trading in symbol, or everywhere for MARKET_WIDE. A minutes value of
+// zero keeps the halt until it is resumed.
+func (r *HaltServiceImp) Halt(ctx context.Context, symbol string, reason string, haltedBy string, minutes int) (*Halt, error) {
+	return r.saveHalt(ctx, normalizeSymbol(symbol), reason, HALT_SOURCE_MANUAL, haltedBy, minutes)
+}
+
+func (r *HaltServiceImp) Resume(ctx context.Context, symbol string) (bool, error) {
+	return r.repo.DeleteHalt(ctx, normalizeSymbol(symbol))
+}
+
+// GetActiveHalt returns the market wide halt if there is one, else the symbol's halt, else nil.
+func (r *HaltServiceImp) GetActiveHalt(ctx context.Context, symbol string) (*Halt, error) {
+	halt, err := r.repo.GetHalt(ctx, MARKET_WIDE)
+	if err != nil || halt != nil {
+		return halt, err
+	}
+	return r.repo.GetHalt(ctx, normalizeSymbol(symbol))
+}
+
+func (r *HaltServiceImp) ListHalts(ctx context.Context) ([]*Halt, error) {
+	halts, err := r.repo.ListHalts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(halts, func(i, j int) bool {
+		return halts[i].Symbol < halts[j].Symbol
+	})
+	return halts, nil
+}
+
+// ObservePrice is the circuit breaker. The first price seen in a window becomes the
+// reference and any later price that moves too far from it halts the symbol.
+func (r *HaltServiceImp) ObservePrice(ctx context.Context, symbol string, price float64) (*Halt, error) {
+	if cfg.HaltConfig.CircuitBreakerPct <= 0 || price <= 0 {
+		return nil, nil
+	}
+	symbol = normalizeSymbol(symbol)
+	reference, ok := r.repo.GetReferencePrice(ctx, symbol)
+	if !ok || reference <= 0 {
+		return nil, r.repo.SetReferencePrice(ctx, symbol, price, cfg.HaltConfig.WindowMinutes)
+	}
+	move := math.Abs(price-reference) / reference * 100
+	if move <= cfg.HaltConfig.CircuitBreakerPct {
+		return nil, nil
+	}
+	reason := fmt.Sprintf("price moved %.2f%% from %.2f to %.2f within %d minutes", move, reference, price, cfg.HaltConfig.WindowMinutes)
+	halt, err := r.saveHalt(ctx, symbol, reason, HALT_SOURCE_CIRCUIT_BREAKER, "system", cfg.HaltConfig.AutoHaltMinutes)
+	if err != nil {
+		return nil, err
+	}
+	// start a fresh window from the price that tripped the breaker
+	return halt, r.repo.SetReferencePrice(ctx, symbol, price, cfg.HaltConfig.WindowMinutes)
+}
+
+func (r *HaltServiceImp) saveHalt(ctx context.Context, symbol string, reason string, source string, haltedBy string, minutes int) (*Halt, error) {
+	now := time.Now()
+	halt := &Halt{
+		Symbol:   symbol,
+		Reason:   reason,
+		Source:   source,
+		HaltedBy: haltedBy,
+		HaltedAt: now.Unix(),
+	}
+	if minutes > 0 {
+		halt.ExpiresAt = now.Add(time.Duration(minutes) * time.Minute).Unix()
+	}
+	if err := r.repo.SaveHalt(ctx, halt, minutes); err != nil {
+		return nil, err
+	}
+	return halt, nil
+}
+
+func normalizeSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return MARKET_WIDE
+	}
+	return symbol
+}
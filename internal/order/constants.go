@@ -1,29 +1,57 @@
-package order
-
-const (
-	STATUS_PLACED        = "placed"
-	STATUS_COMPLETED      = "completed"
-	STATUS_CANCELLED = "cancelled"
-)
-
-var allowedStatus []string = []string{
-	STATUS_PLACED, STATUS_COMPLETED, STATUS_CANCELLED,
-}
-
-var AllowedTransitions map[string]map[string]bool = map[string]map[string]bool{
-	STATUS_PLACED: {
-		STATUS_COMPLETED: true,
-		STATUS_CANCELLED:true,
-		STATUS_PLACED:true,
-	},
-	STATUS_COMPLETED: {
-		STATUS_CANCELLED: true,
-		STATUS_COMPLETED:true,
-		STATUS_PLACED:false,
-	},
-	STATUS_CANCELLED: {
-		STATUS_CANCELLED:true,
-		STATUS_COMPLETED:false,
-		STATUS_PLACED:false,
-	},
-}
+package order
+
+const (
+	STATUS_PLACED        = "placed"
+	STATUS_COMPLETED      = "completed"
+	STATUS_CANCELLED = "cancelled"
+)
+
+const (
+	SIDE_BUY  = "BUY"
+	SIDE_SELL = "SELL"
+)
+
+const (
+	TIME_IN_FORCE_DAY = "DAY"
+	TIME_IN_FORCE_GTC = "GTC"
+	TIME_IN_FORCE_IOC = "IOC"
+)
+
+// ACTOR_SYSTEM is the actor of status changes nobody asked for, like the simulated fills.
+const ACTOR_SYSTEM = "system"
+
+// reasons recorded in the order events
+const (
+	EVENT_REASON_PLACED         = "order placed"
+	EVENT_REASON_USER_CANCELLED = "cancelled by user"
+	EVENT_REASON_FILLED         = "filled by operator"
+	EVENT_REASON_SIMULATED_FILL = "filled by simulator"
+	EVENT_REASON_EXPIRED        = "expired at session close"
+)
+
+// DEFAULT_CURRENCY prices orders whose symbol is missing from the security master.
+const DEFAULT_CURRENCY = "USD"
+
+const REASON_TRADING_HALTED = "TRADING_HALTED"
+
+var allowedStatus []string = []string{
+	STATUS_PLACED, STATUS_COMPLETED, STATUS_CANCELLED,
+}
+
+var AllowedTransitions map[string]map[string]bool = map[string]map[string]bool{
+	STATUS_PLACED: {
+		STATUS_COMPLETED: true,
+		STATUS_CANCELLED:true,
+		STATUS_PLACED:true,
+	},
+	STATUS_COMPLETED: {
+		STATUS_CANCELLED: true,
+		STATUS_COMPLETED:true,
+		STATUS_PLACED:false,
+	},
+	STATUS_CANCELLED: {
+		STATUS_CANCELLED:true,
+		STATUS_COMPLETED:false,
+		STATUS_PLACED:false,
+	},
+}
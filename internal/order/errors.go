@@ -0,0 +1,52 @@
+package order
+
+import (
+	"errors"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+	"github.com/tanmaygupta069/order-service-go/internal/security"
+)
+
+const (
+	REASON_INVALID_FIELD       = "INVALID_FIELD"
+	REASON_ORDER_NOT_FOUND     = "ORDER_NOT_FOUND"
+	REASON_NOT_ORDER_OWNER     = "NOT_ORDER_OWNER"
+	REASON_INVALID_TRANSITION  = "INVALID_STATUS_TRANSITION"
+	REASON_MARKET_CLOSED       = "MARKET_CLOSED"
+	REASON_PRICE_UNAVAILABLE   = "PRICE_UNAVAILABLE"
+	REASON_UNKNOWN_SYMBOL      = "UNKNOWN_SYMBOL"
+	REASON_SYMBOL_NOT_TRADABLE = "SYMBOL_NOT_TRADABLE"
+	REASON_NOT_HALTED          = "NOT_HALTED"
+	REASON_ORDER_EXPIRED       = "ORDER_EXPIRED"
+	REASON_HALTS_UNAVAILABLE   = "HALTS_UNAVAILABLE"
+)
+
+var (
+	ErrOrderNotFound     = apperror.NotFound(REASON_ORDER_NOT_FOUND, "you have no such order")
+	ErrNotOrderOwner     = apperror.PermissionDenied(REASON_NOT_ORDER_OWNER, "can't cancel order which is not yours")
+	ErrInvalidTransition = apperror.FailedPrecondition(REASON_INVALID_TRANSITION, "invalid state change")
+	ErrMarketClosed      = apperror.FailedPrecondition(REASON_MARKET_CLOSED, "market is closed")
+	ErrPriceUnavailable  = apperror.Unavailable(REASON_PRICE_UNAVAILABLE, "unable to get price")
+	ErrTradingHalted     = apperror.Rejected(REASON_TRADING_HALTED, "trading is halted")
+	ErrUnknownSymbol     = apperror.NotFound(REASON_UNKNOWN_SYMBOL, "unknown symbol")
+	ErrSymbolNotTradable = apperror.InvalidField(REASON_SYMBOL_NOT_TRADABLE, "symbol", "symbol is not tradable")
+	ErrNotHalted         = apperror.NotFound(REASON_NOT_HALTED, "trading is not halted")
+	ErrOrderExpired      = apperror.FailedPrecondition(REASON_ORDER_EXPIRED, "day order expired at the close of its trading day")
+	ErrHaltsUnavailable  = apperror.Unavailable(REASON_HALTS_UNAVAILABLE, "unable to check trading halts")
+)
+
+// invalidField is a validation failure of one request field.
+func invalidField(field string, message string) error {
+	return apperror.InvalidField(REASON_INVALID_FIELD, field, message)
+}
+
+// symbolError maps errors from the security master to order errors.
+func symbolError(err error) error {
+	if errors.Is(err, security.ErrUnknownSymbol) {
+		return ErrUnknownSymbol.Withf("%s", err.Error())
+	}
+	if errors.Is(err, security.ErrSymbolNotTradable) {
+		return ErrSymbolNotTradable.Withf("%s", err.Error())
+	}
+	return err
+}
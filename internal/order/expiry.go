@@ -0,0 +1,73 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+// expiryPageSize is how many placed orders ExpireDayOrders reads at a time.
+const expiryPageSize = 500
+
+// isExpired reports whether order is a placed day order whose trading day has closed.
+// Orders from before creation times were recorded can't be placed in a day and never
+// expire.
+func (r *OrderServiceImp) isExpired(ctx context.Context, order *mysql.Orders) bool {
+	if order.OrderStatus != STATUS_PLACED || order.TimeInForce != TIME_IN_FORCE_DAY || order.CreatedAt.IsZero() {
+		return false
+	}
+	end := r.calendar.SessionEnd(r.exchangeOf(ctx, order.Symbol), order.CreatedAt, order.AllowAfterHours)
+	return !end.IsZero() && !time.Now().Before(end)
+}
+
+func (r *OrderServiceImp) expireOrder(ctx context.Context, order *mysql.Orders) (*mysql.Orders, error) {
+	fmt.Printf("day order %s expired at the session close, cancelling it\n", order.OrderId)
+	return r.repo.UpdateOrderStatus(ctx, order, STATUS_CANCELLED, ACTOR_SYSTEM, EVENT_REASON_EXPIRED)
+}
+
+// ExpireDayOrders cancels every placed day order whose trading day has closed and returns
+// how many it cancelled.
+func (r *OrderServiceImp) ExpireDayOrders(ctx context.Context) (int, error) {
+	expired := 0
+	after := ""
+	for {
+		orders, err := r.repo.GetPlacedOrders(ctx, after, expiryPageSize)
+		if err != nil {
+			return expired, err
+		}
+		for _, order := range orders {
+			if !r.isExpired(ctx, order) {
+				continue
+			}
+			if _, err = r.expireOrder(ctx, order); err != nil {
+				return expired, err
+			}
+			expired++
+		}
+		if len(orders) < expiryPageSize {
+			return expired, nil
+		}
+		after = orders[len(orders)-1].OrderId
+	}
+}
+
+// ExpireDayOrdersEvery runs ExpireDayOrders on service every interval until ctx is done.
+// Fills expire the orders they come across as well, this catches the ones nobody touches.
+func ExpireDayOrdersEvery(ctx context.Context, service OrderService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if count, err := service.ExpireDayOrders(ctx); err != nil {
+				fmt.Printf("error expiring day orders : %v\n", err)
+			} else if count > 0 {
+				fmt.Printf("expired %d day orders\n", count)
+			}
+		}
+	}
+}
@@ -1,380 +1,494 @@
-package order
-
-import (
-	"context"
-	"fmt"
-	"net/http"
-	"strings"
-
-	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
-	common "github.com/tanmaygupta069/order-service-go/generated/common"
-	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
-	"google.golang.org/grpc/metadata"
-)
-
-type OrderController struct {
-	service OrderService
-	auth auth.AuthPackage
-	OrderPb.UnimplementedOrderServiceServer
-}
-
-func NewOrderController() *OrderController {
-	return &OrderController{
-		service: NewOrderService(),
-		auth: auth.NewAuthPackage(),
-	}
-}
-
-func (s *OrderController) PlaceOrder(ctx context.Context, req *OrderPb.OrderRequest) (*OrderPb.OrderResponse, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("missing metadata")
-	}
-	token, err := s.auth.GetTokenFromMetadata(md)
-	if err != nil {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	req.OrderType=strings.ToUpper(req.OrderType)
-	if req.OrderType == "" || req.Symbol == "" {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "symbol,ordertype,quantity can't be empty",
-			},
-		}, nil
-	}
-	if req.Quantity <= 0 {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "quantity must be greater than zero",
-			},
-		}, nil
-	}
-
-	if req.Symbol == "" {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "symbol must have atleast 1 letter",
-			},
-		}, nil
-	}
-
-	if strings.ToUpper(req.OrderType) != "BUY" && strings.ToUpper(req.OrderType) != "SELL" {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "order type must be either buy or sell",
-			},
-		}, nil
-	}
-
-	email, err := s.auth.ExtractUserIDFromToken(token)
-	if err != nil {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	if req.OrderType == "sell" || req.OrderType == "SELL"{
-		ok,err:=s.service.CheckStockQuantity(email,req.Symbol,req.Quantity);
-		if err!=nil{
-			return &OrderPb.OrderResponse{
-				Response: &common.Response{
-					Code:    http.StatusInternalServerError,
-					Message: err.Error(),
-				},
-			}, nil
-		}
-		if ok==false && err==nil{
-			return &OrderPb.OrderResponse{
-				Response: &common.Response{
-					Code:    http.StatusBadRequest,
-					Message: "can't sell less than your current quantity",
-				},
-			}, nil
-		}
-	} 
-	stockPrice, err := s.service.GetStockPrice(strings.ToUpper(req.Symbol))
-	if err != nil {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-	order := Orders{
-		OrderId:       s.service.GenerateOrderId(),
-		UserId:        email,
-		Symbol:        strings.ToUpper(req.Symbol),
-		PricePerStock: stockPrice,
-		Quantity:      req.Quantity,
-		TotalPrice:    stockPrice * float64(req.Quantity),
-		OrderType:     req.OrderType,
-		OrderStatus:   "placed",
-	}
-	res, err := s.service.PlaceOrder(&order)
-	if err != nil {
-		return &OrderPb.OrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, err
-	}
-	return &OrderPb.OrderResponse{
-		Order: &OrderPb.Order{
-			OrderId:       res.OrderId,
-			Symbol:        strings.ToUpper(req.Symbol),
-			Quantity:      res.Quantity,
-			PricePerStock: res.PricePerStock,
-			TotalPrice:    res.TotalPrice,
-			OrderType:     res.OrderType,
-			OrderStatus:   res.OrderStatus,
-		},
-		Response: &common.Response{
-			Code:    http.StatusCreated,
-			Message: http.StatusText(http.StatusCreated),
-		},
-	}, nil
-}
-
-func (s *OrderController) CancelOrder(ctx context.Context, req *OrderPb.CancelOrderRequest) (*OrderPb.CancelOrderResponse, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("missing metadata")
-	}
-	token, err := s.auth.GetTokenFromMetadata(md)
-	if err != nil {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	if req.OrderId == "" {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "orderId can't be empty",
-			},
-		}, nil
-	}
-	fmt.Print(req.OrderId)
-
-	if !IsValidUUID(req.OrderId) {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "not a valid format for orderId",
-			},
-		}, nil
-	}
-	email, err := s.auth.ExtractUserIDFromToken(token)
-	if err != nil {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	valid, err := s.service.IDORCheck(email, req.OrderId)
-	if valid == false && err == nil {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusUnauthorized,
-				Message: "can't cancel order which is not yours",
-			},
-		}, nil
-	} else if err != nil {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusNotFound,
-				Message: "you have no such order",
-			},
-		}, nil
-	}
-
-	order, err := s.service.CancelOrder(req.OrderId)
-	if err != nil {
-		return &OrderPb.CancelOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	return &OrderPb.CancelOrderResponse{
-		Order: &OrderPb.Order{
-			OrderId:       order.OrderId,
-			Symbol:        order.Symbol,
-			Quantity:      order.Quantity,
-			PricePerStock: order.PricePerStock,
-			TotalPrice:    order.TotalPrice,
-			OrderType:     order.OrderType,
-			OrderStatus: order.OrderStatus,
-		},
-		Response: &common.Response{
-			Code:    http.StatusOK,
-			Message: http.StatusText(http.StatusOK),
-		},
-	}, nil
-}
-
-func (s *OrderController) GetOrderHistory(ctx context.Context, req *OrderPb.OrderHistoryRequest) (*OrderPb.OrderHistoryResponse, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("missing metadata")
-	}
-
-	token, err := s.auth.GetTokenFromMetadata(md)
-	if err != nil {
-		return &OrderPb.OrderHistoryResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	email, err := s.auth.ExtractUserIDFromToken(token)
-	if err != nil {
-		return &OrderPb.OrderHistoryResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	res, err := s.service.GetOrderHistory(email)
-	if err != nil {
-		return &OrderPb.OrderHistoryResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-	orders := make([]*OrderPb.Order, 0)
-
-	for _, order := range res {
-		orders = append(orders, &OrderPb.Order{
-			OrderId:       order.OrderId,
-			Symbol:        order.Symbol,
-			Quantity:      order.Quantity,
-			PricePerStock: order.PricePerStock,
-			TotalPrice:    order.TotalPrice,
-			OrderType:     order.OrderType,
-			OrderStatus: order.OrderStatus,
-		})
-	}
-
-	return &OrderPb.OrderHistoryResponse{
-		Orders: orders,
-		Response: &common.Response{
-			Code:    http.StatusOK,
-			Message: http.StatusText(http.StatusOK),
-		},
-	}, nil
-}
-
-func (s *OrderController) GetCurrentPrice(ctx context.Context, req *OrderPb.GetCurrentPriceRequest) (*OrderPb.GetCurrentPriceResponse, error) {
-
-	if req.Symbol == "" {
-		return &OrderPb.GetCurrentPriceResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "symbol can't be empty",
-			},
-		}, nil
-	}
-	if len(req.Symbol) <= 0 {
-		return &OrderPb.GetCurrentPriceResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "symbol must have atleast 1 letter",
-			},
-		}, nil
-	}
-
-	req.Symbol=strings.ToUpper(req.Symbol)
-	price, err := s.service.GetStockPrice(req.Symbol)
-	if err != nil {
-		return &OrderPb.GetCurrentPriceResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-	return &OrderPb.GetCurrentPriceResponse{
-		Price: price,
-		Response: &common.Response{
-			Code:    http.StatusOK,
-			Message: http.StatusText(http.StatusOK),
-		},
-	}, nil
-}
-
-func (s *OrderController)CompleteOrder(ctx context.Context,req *OrderPb.CompleteOrderRequest)(*OrderPb.CompleteOrderResponse,error){
-	if req.OrderId == "" {
-		return &OrderPb.CompleteOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "orderId can't be empty",
-			},
-		}, nil
-	}
-
-	if !IsValidUUID(req.OrderId) {
-		return &OrderPb.CompleteOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusBadRequest,
-				Message: "not a valid format for orderId",
-			},
-		}, nil
-	}
-
-	order, err := s.service.CompleteOrder(req.OrderId)
-	if err != nil {
-		return &OrderPb.CompleteOrderResponse{
-			Response: &common.Response{
-				Code:    http.StatusInternalServerError,
-				Message: err.Error(),
-			},
-		}, nil
-	}
-
-	return &OrderPb.CompleteOrderResponse{
-		Response: &common.Response{
-			Code: http.StatusOK,
-			Message: "ordered completed",
-		},
-		Order: &OrderPb.Order{
-			OrderId: order.OrderId,
-			Symbol: order.Symbol,
-			Quantity: order.Quantity,
-			PricePerStock: order.PricePerStock,
-			TotalPrice: order.TotalPrice,
-			OrderType: order.OrderType,
-			OrderStatus: order.OrderStatus,
-		},
-	},nil
-}
\ No newline at end of file
+package order
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
+	common "github.com/tanmaygupta069/order-service-go/generated/common"
+	"github.com/tanmaygupta069/order-service-go/internal/market"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+type OrderController struct {
+	service OrderService
+	OrderPb.UnimplementedOrderServiceServer
+}
+
+func NewOrderController() *OrderController {
+	return &OrderController{
+		service: NewOrderService(),
+	}
+}
+
+func (s *OrderController) PlaceOrder(ctx context.Context, req *OrderPb.OrderRequest) (*OrderPb.OrderResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	req.OrderType=strings.ToUpper(req.OrderType)
+	if req.OrderType == "" || req.Symbol == "" {
+		return nil, apperror.New(apperror.KIND_INVALID_ARGUMENT, REASON_INVALID_FIELD, "symbol,ordertype,quantity can't be empty").
+			WithField("symbol", "can't be empty").
+			WithField("orderType", "can't be empty")
+	}
+	if req.OrderType != SIDE_BUY && req.OrderType != SIDE_SELL {
+		return nil, invalidField("orderType", "order type must be either buy or sell")
+	}
+
+	// v1 has no time in force, its orders are day orders
+	res, err := s.service.SubmitOrder(ctx, &OrderRequest{
+		UserId:          principal.UserId,
+		Tier:            principal.Tier,
+		Symbol:          req.Symbol,
+		Side:            req.OrderType,
+		Quantity:        req.Quantity,
+		TimeInForce:     TIME_IN_FORCE_DAY,
+		AllowPreMarket:  req.AllowPreMarket,
+		AllowAfterHours: req.AllowAfterHours,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OrderPb.OrderResponse{
+		Order: &OrderPb.Order{
+			OrderId:       res.OrderId,
+			Symbol:        res.Symbol,
+			Quantity:      res.Quantity,
+			PricePerStock: res.PricePerStock.InexactFloat64(),
+			TotalPrice:    res.TotalPrice.InexactFloat64(),
+			OrderType:     res.OrderType,
+			OrderStatus:   res.OrderStatus,
+			CreatedAt:     unixTime(&res.CreatedAt),
+			UpdatedAt:     unixTime(&res.UpdatedAt),
+		},
+		Response: &common.Response{
+			Code:    http.StatusCreated,
+			Message: http.StatusText(http.StatusCreated),
+		},
+	}, nil
+}
+
+func (s *OrderController) CancelOrder(ctx context.Context, req *OrderPb.CancelOrderRequest) (*OrderPb.CancelOrderResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if err := validateOrderId(req.OrderId); err != nil {
+		return nil, err
+	}
+
+	order, err := s.service.CancelUserOrder(ctx, principal.UserId, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderPb.CancelOrderResponse{
+		Order: toOrder(order),
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController) GetOrderHistory(ctx context.Context, req *OrderPb.OrderHistoryRequest) (*OrderPb.OrderHistoryResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+
+	query, err := historyQuery(principal.UserId, req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.service.GetOrderHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*OrderPb.Order, 0)
+
+	for _, order := range res.Orders {
+		orders = append(orders, toOrder(order))
+	}
+
+	return &OrderPb.OrderHistoryResponse{
+		Orders: orders,
+		NextPageToken: res.NextPageToken,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController) GetCurrentPrice(ctx context.Context, req *OrderPb.GetCurrentPriceRequest) (*OrderPb.GetCurrentPriceResponse, error) {
+
+	if req.Symbol == "" {
+		return nil, invalidField("symbol", "symbol can't be empty")
+	}
+
+	req.Symbol=strings.ToUpper(req.Symbol)
+	if _, err := s.service.ValidateSymbol(ctx, req.Symbol); err != nil {
+		return nil, symbolError(err)
+	}
+	quote, err := s.service.GetStockPrice(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderPb.GetCurrentPriceResponse{
+		Price: quote.Price.InexactFloat64(),
+		Source: quote.Source,
+		Timestamp: quote.Timestamp,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController)CompleteOrder(ctx context.Context,req *OrderPb.CompleteOrderRequest)(*OrderPb.CompleteOrderResponse,error){
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if err := validateOrderId(req.OrderId); err != nil {
+		return nil, err
+	}
+
+	order, err := s.service.CompleteOrder(ctx, req.OrderId, principal.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderPb.CompleteOrderResponse{
+		Response: &common.Response{
+			Code: http.StatusOK,
+			Message: "ordered completed",
+		},
+		Order: toOrder(order),
+	},nil
+}
+
+// GetOrder returns one order with its events. Like GetOrderEvents operators see any order,
+// but for users an order of someone else is not found rather than not theirs.
+func (s *OrderController) GetOrder(ctx context.Context, req *OrderPb.GetOrderRequest) (*OrderPb.GetOrderResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if err := validateOrderId(req.OrderId); err != nil {
+		return nil, err
+	}
+	var order *mysql.Orders
+	var err error
+	if principal.HasRole(auth.ROLE_OPERATOR) {
+		order, err = s.service.GetOrder(ctx, req.OrderId)
+	} else {
+		order, err = s.service.GetUserOrder(ctx, principal.UserId, req.OrderId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.orderEvents(ctx, order.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderPb.GetOrderResponse{
+		Order:  toOrder(order),
+		Events: events,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+// GetOrderEvents returns the status changes of an order. Users only see their own orders,
+// operators see any order so they can answer support tickets. Like GetOrder, orders of
+// other users are reported as not found so order ids can't be probed for existence.
+func (s *OrderController) GetOrderEvents(ctx context.Context, req *OrderPb.GetOrderEventsRequest) (*OrderPb.GetOrderEventsResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if err := validateOrderId(req.OrderId); err != nil {
+		return nil, err
+	}
+	if !principal.HasRole(auth.ROLE_OPERATOR) {
+		valid, err := s.service.IDORCheck(ctx, principal.UserId, req.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, ErrOrderNotFound
+		}
+	}
+
+	events, err := s.orderEvents(ctx, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderPb.GetOrderEventsResponse{
+		Events: events,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController) SearchSymbols(ctx context.Context, req *OrderPb.SearchSymbolsRequest) (*OrderPb.SearchSymbolsResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, invalidField("query", "query can't be empty")
+	}
+
+	res, err := s.service.SearchSymbols(ctx, req.Query, int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	securities := make([]*OrderPb.Security, 0)
+	for _, security := range res {
+		securities = append(securities, &OrderPb.Security{
+			Symbol:   security.Symbol,
+			Name:     security.Name,
+			Exchange: security.Exchange,
+			Currency: security.Currency,
+			LotSize:  security.LotSize,
+			TickSize: security.TickSize,
+			Tradable: security.Tradable,
+			Sector:   security.Sector,
+		})
+	}
+
+	return &OrderPb.SearchSymbolsResponse{
+		Securities: securities,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController) GetMarketStatus(ctx context.Context, req *OrderPb.GetMarketStatusRequest) (*OrderPb.GetMarketStatusResponse, error) {
+	exchange := strings.ToUpper(req.Exchange)
+	if req.Symbol != "" {
+		security, err := s.service.GetSecurity(ctx, req.Symbol)
+		if err != nil {
+			return nil, symbolError(err)
+		}
+		exchange = security.Exchange
+	}
+
+	status := s.service.GetMarketStatus(exchange)
+	res := &OrderPb.GetMarketStatusResponse{
+		Exchange: status.Exchange,
+		Session:  status.Session,
+		IsOpen:   status.IsOpen,
+		Timezone: status.Timezone,
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}
+	halts, err := s.service.ListHalts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, halt := range halts {
+		if halt.Symbol == market.MARKET_WIDE {
+			res.MarketHalted = true
+		} else if req.Symbol != "" && halt.Symbol != strings.ToUpper(req.Symbol) {
+			continue
+		}
+		res.Halts = append(res.Halts, toTradingHalt(halt))
+	}
+	if !status.NextOpen.IsZero() {
+		res.NextOpen = status.NextOpen.Unix()
+	}
+	if !status.NextClose.IsZero() {
+		res.NextClose = status.NextClose.Unix()
+	}
+	return res, nil
+}
+
+func (s *OrderController) HaltTrading(ctx context.Context, req *OrderPb.HaltTradingRequest) (*OrderPb.HaltTradingResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if req.Symbol == "" && !req.MarketWide {
+		return nil, invalidField("symbol", "either symbol or marketWide must be set")
+	}
+	if req.Reason == "" {
+		return nil, invalidField("reason", "reason can't be empty")
+	}
+	if req.DurationMinutes < 0 {
+		return nil, invalidField("durationMinutes", "durationMinutes can't be negative")
+	}
+	symbol := market.MARKET_WIDE
+	if !req.MarketWide {
+		security, err := s.service.GetSecurity(ctx, req.Symbol)
+		if err != nil {
+			return nil, symbolError(err)
+		}
+		symbol = security.Symbol
+	}
+
+	halt, err := s.service.HaltTrading(ctx, symbol, req.Reason, principal.UserId, int(req.DurationMinutes))
+	if err != nil {
+		return nil, err
+	}
+	return &OrderPb.HaltTradingResponse{
+		Halt: toTradingHalt(halt),
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func (s *OrderController) ResumeTrading(ctx context.Context, req *OrderPb.ResumeTradingRequest) (*OrderPb.ResumeTradingResponse, error) {
+	if req.Symbol == "" && !req.MarketWide {
+		return nil, invalidField("symbol", "either symbol or marketWide must be set")
+	}
+	symbol := market.MARKET_WIDE
+	if !req.MarketWide {
+		symbol = strings.ToUpper(req.Symbol)
+	}
+
+	resumed, err := s.service.ResumeTrading(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, ErrNotHalted.Withf("%s is not halted", symbol)
+	}
+	return &OrderPb.ResumeTradingResponse{
+		Response: &common.Response{
+			Code:    http.StatusOK,
+			Message: http.StatusText(http.StatusOK),
+		},
+	}, nil
+}
+
+func toTradingHalt(halt *market.Halt) *OrderPb.TradingHalt {
+	return &OrderPb.TradingHalt{
+		Symbol:    halt.Symbol,
+		Reason:    halt.Reason,
+		Source:    halt.Source,
+		HaltedBy:  halt.HaltedBy,
+		HaltedAt:  halt.HaltedAt,
+		ExpiresAt: halt.ExpiresAt,
+	}
+}
+
+func (s *OrderController) orderEvents(ctx context.Context, orderId string) ([]*OrderPb.OrderEvent, error) {
+	res, err := s.service.GetOrderEvents(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*OrderPb.OrderEvent, 0, len(res))
+	for _, event := range res {
+		events = append(events, &OrderPb.OrderEvent{
+			FromStatus: event.FromStatus,
+			ToStatus:   event.ToStatus,
+			Actor:      event.Actor,
+			Reason:     event.Reason,
+			Timestamp:  unixTime(&event.CreatedAt),
+		})
+	}
+	return events, nil
+}
+
+// historyQuery validates the filters of an order history request.
+func historyQuery(userId string, req *OrderPb.OrderHistoryRequest) (*OrderHistoryQuery, error) {
+	query := &OrderHistoryQuery{
+		UserId:     userId,
+		Status:     strings.ToLower(req.OrderStatus),
+		Symbol:     strings.ToUpper(req.Symbol),
+		Side:       strings.ToUpper(req.OrderType),
+		Descending: true,
+		PageSize:   int(req.PageSize),
+		PageToken:  req.PageToken,
+	}
+	if query.Status != "" && !slices.Contains(allowedStatus, query.Status) {
+		return nil, invalidField("orderStatus", "order status must be one of placed, completed or cancelled")
+	}
+	if query.Side != "" && query.Side != SIDE_BUY && query.Side != SIDE_SELL {
+		return nil, invalidField("orderType", "order type must be either buy or sell")
+	}
+	switch strings.ToLower(req.SortOrder) {
+	case "", SORT_DESC:
+	case SORT_ASC:
+		query.Descending = false
+	default:
+		return nil, invalidField("sortOrder", "sort order must be either asc or desc")
+	}
+	if req.PageSize < 0 {
+		return nil, invalidField("pageSize", "pageSize can't be negative")
+	}
+	if req.CreatedFrom < 0 || req.CreatedTo < 0 {
+		return nil, invalidField("createdFrom", "dates can't be negative")
+	}
+	if req.CreatedFrom > 0 {
+		query.From = time.Unix(req.CreatedFrom, 0)
+	}
+	if req.CreatedTo > 0 {
+		query.To = time.Unix(req.CreatedTo, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, invalidField("createdTo", "createdTo must be after createdFrom")
+	}
+	return query, nil
+}
+
+func toOrder(order *mysql.Orders) *OrderPb.Order {
+	return &OrderPb.Order{
+		OrderId:       order.OrderId,
+		Symbol:        order.Symbol,
+		Quantity:      order.Quantity,
+		PricePerStock: order.PricePerStock.InexactFloat64(),
+		TotalPrice:    order.TotalPrice.InexactFloat64(),
+		OrderType:     order.OrderType,
+		OrderStatus:   order.OrderStatus,
+		CreatedAt:     unixTime(&order.CreatedAt),
+		UpdatedAt:     unixTime(&order.UpdatedAt),
+		CompletedAt:   unixTime(order.CompletedAt),
+		CancelledAt:   unixTime(order.CancelledAt),
+	}
+}
+
+// unixTime is t in unix seconds, 0 for times never set, like those of orders placed before
+// the columns existed.
+func unixTime(t *time.Time) int64 {
+	if t == nil || t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func validateOrderId(orderId string) error {
+	if orderId == "" {
+		return invalidField("orderId", "orderId can't be empty")
+	}
+	if !IsValidUUID(orderId) {
+		return invalidField("orderId", "not a valid format for orderId")
+	}
+	return nil
+}
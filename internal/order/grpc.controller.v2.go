@@ -0,0 +1,234 @@
+package order
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	orderv2 "github.com/tanmaygupta069/order-service-go/generated/order/v2"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var sidesV2 = map[orderv2.Side]string{
+	orderv2.Side_SIDE_BUY:  SIDE_BUY,
+	orderv2.Side_SIDE_SELL: SIDE_SELL,
+}
+
+var statusesV2 = map[string]orderv2.OrderStatus{
+	STATUS_PLACED:    orderv2.OrderStatus_ORDER_STATUS_PLACED,
+	STATUS_COMPLETED: orderv2.OrderStatus_ORDER_STATUS_COMPLETED,
+	STATUS_CANCELLED: orderv2.OrderStatus_ORDER_STATUS_CANCELLED,
+}
+
+var timesInForceV2 = map[orderv2.TimeInForce]string{
+	orderv2.TimeInForce_TIME_IN_FORCE_UNSPECIFIED: TIME_IN_FORCE_DAY,
+	orderv2.TimeInForce_TIME_IN_FORCE_DAY:         TIME_IN_FORCE_DAY,
+	orderv2.TimeInForce_TIME_IN_FORCE_GTC:         TIME_IN_FORCE_GTC,
+	orderv2.TimeInForce_TIME_IN_FORCE_IOC:         TIME_IN_FORCE_IOC,
+}
+
+// OrderControllerV2 serves order.v2.OrderService. Like the v1 controller it only
+// translates messages, the work is done by OrderService.
+type OrderControllerV2 struct {
+	service OrderService
+	orderv2.UnimplementedOrderServiceServer
+}
+
+func NewOrderControllerV2() *OrderControllerV2 {
+	return &OrderControllerV2{
+		service: NewOrderService(),
+	}
+}
+
+func (s *OrderControllerV2) PlaceOrder(ctx context.Context, req *orderv2.PlaceOrderRequest) (*orderv2.PlaceOrderResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if strings.TrimSpace(req.Symbol) == "" {
+		return nil, invalidField("symbol", "symbol can't be empty")
+	}
+	side, ok := sidesV2[req.Side]
+	if !ok {
+		return nil, invalidField("side", "side must be either SIDE_BUY or SIDE_SELL")
+	}
+	if req.Type != orderv2.OrderType_ORDER_TYPE_UNSPECIFIED && req.Type != orderv2.OrderType_ORDER_TYPE_MARKET {
+		return nil, invalidField("type", "only market orders are supported")
+	}
+	timeInForce, ok := timesInForceV2[req.TimeInForce]
+	if !ok {
+		return nil, invalidField("time_in_force", "unknown time in force")
+	}
+
+	order, err := s.service.SubmitOrder(ctx, &OrderRequest{
+		UserId:          principal.UserId,
+		Tier:            principal.Tier,
+		Symbol:          req.Symbol,
+		Side:            side,
+		Quantity:        req.Quantity,
+		TimeInForce:     timeInForce,
+		AllowPreMarket:  req.AllowPreMarket,
+		AllowAfterHours: req.AllowAfterHours,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.PlaceOrderResponse{
+		Order: s.toOrderV2(ctx, &mysql.Orders{
+			OrderId:         order.OrderId,
+			UserId:          order.UserId,
+			Symbol:          order.Symbol,
+			PricePerStock:   order.PricePerStock,
+			Quantity:        order.Quantity,
+			TotalPrice:      order.TotalPrice,
+			OrderType:       order.OrderType,
+			OrderStatus:     order.OrderStatus,
+			AllowPreMarket:  order.AllowPreMarket,
+			AllowAfterHours: order.AllowAfterHours,
+			TimeInForce:     order.TimeInForce,
+			CreatedAt:       order.CreatedAt,
+			UpdatedAt:       order.UpdatedAt,
+		}, make(map[string]string)),
+	}, nil
+}
+
+func (s *OrderControllerV2) CancelOrder(ctx context.Context, req *orderv2.CancelOrderRequest) (*orderv2.CancelOrderResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if err := validateOrderId(req.OrderId); err != nil {
+		return nil, err
+	}
+	order, err := s.service.CancelUserOrder(ctx, principal.UserId, req.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.CancelOrderResponse{
+		Order: s.toOrderV2(ctx, order, make(map[string]string)),
+	}, nil
+}
+
+func (s *OrderControllerV2) ListOrders(ctx context.Context, req *orderv2.ListOrdersRequest) (*orderv2.ListOrdersResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, auth.ErrMissingPrincipal
+	}
+
+	if req.PageSize < 0 {
+		return nil, invalidField("page_size", "page_size can't be negative")
+	}
+	res, err := s.service.GetOrderHistory(ctx, &OrderHistoryQuery{
+		UserId:     principal.UserId,
+		Descending: true,
+		PageSize:   int(req.PageSize),
+		PageToken:  req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+	currencies := make(map[string]string)
+	orders := make([]*orderv2.Order, 0, len(res.Orders))
+	for _, order := range res.Orders {
+		orders = append(orders, s.toOrderV2(ctx, order, currencies))
+	}
+	return &orderv2.ListOrdersResponse{
+		Orders:        orders,
+		NextPageToken: res.NextPageToken,
+	}, nil
+}
+
+func (s *OrderControllerV2) GetQuote(ctx context.Context, req *orderv2.GetQuoteRequest) (*orderv2.GetQuoteResponse, error) {
+	if strings.TrimSpace(req.Symbol) == "" {
+		return nil, invalidField("symbol", "symbol can't be empty")
+	}
+	security, err := s.service.ValidateSymbol(ctx, strings.ToUpper(req.Symbol))
+	if err != nil {
+		return nil, symbolError(err)
+	}
+	quote, err := s.service.GetStockPrice(ctx, security.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &orderv2.GetQuoteResponse{
+		Symbol:    security.Symbol,
+		Price:     toMoney(quote.Price, currencyOrDefault(security.Currency)),
+		Source:    quote.Source,
+		QuoteTime: timestamppb.New(time.Unix(quote.Timestamp, 0)),
+	}, nil
+}
+
+// toOrderV2 looks up the currency of the order's symbol, currencies caches the lookups
+// across one response.
+func (s *OrderControllerV2) toOrderV2(ctx context.Context, order *mysql.Orders, currencies map[string]string) *orderv2.Order {
+	currency, ok := currencies[order.Symbol]
+	if !ok {
+		currency = DEFAULT_CURRENCY
+		if security, err := s.service.GetSecurity(ctx, order.Symbol); err == nil {
+			currency = currencyOrDefault(security.Currency)
+		}
+		currencies[order.Symbol] = currency
+	}
+
+	res := &orderv2.Order{
+		OrderId:         order.OrderId,
+		Symbol:          order.Symbol,
+		Type:            orderv2.OrderType_ORDER_TYPE_MARKET,
+		Status:          statusesV2[order.OrderStatus],
+		Quantity:        order.Quantity,
+		Price:           toMoney(order.PricePerStock, currency),
+		Total:           toMoney(order.TotalPrice, currency),
+		AllowPreMarket:  order.AllowPreMarket,
+		AllowAfterHours: order.AllowAfterHours,
+		CreateTime:      toTimestamp(&order.CreatedAt),
+		UpdateTime:      toTimestamp(&order.UpdatedAt),
+		FillTime:        toTimestamp(order.CompletedAt),
+	}
+	for side, name := range sidesV2 {
+		if strings.EqualFold(order.OrderType, name) {
+			res.Side = side
+		}
+	}
+	switch order.TimeInForce {
+	case TIME_IN_FORCE_GTC:
+		res.TimeInForce = orderv2.TimeInForce_TIME_IN_FORCE_GTC
+	case TIME_IN_FORCE_IOC:
+		res.TimeInForce = orderv2.TimeInForce_TIME_IN_FORCE_IOC
+	default:
+		// orders placed before time in force existed are day orders
+		res.TimeInForce = orderv2.TimeInForce_TIME_IN_FORCE_DAY
+	}
+	return res
+}
+
+// toMoney splits amount into units and nanos, rounding to the nearest nano.
+func toMoney(amount decimal.Decimal, currency string) *orderv2.Money {
+	amount = amount.Round(9)
+	units := amount.IntPart()
+	nanos := int32(amount.Sub(decimal.NewFromInt(units)).Shift(9).IntPart())
+	return &orderv2.Money{
+		CurrencyCode: currency,
+		Units:        units,
+		Nanos:        nanos,
+	}
+}
+
+// toTimestamp leaves times which were never set out of the message.
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func currencyOrDefault(currency string) string {
+	if currency == "" {
+		return DEFAULT_CURRENCY
+	}
+	return strings.ToUpper(currency)
+}
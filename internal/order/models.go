@@ -1,16 +1,74 @@
-package order
-
-type Orders struct{
-	UserId string
-	OrderId string
-	Symbol string
-	PricePerStock float64
-	Quantity int32
-	TotalPrice float64
-	OrderType string
-	OrderStatus string
-}
-
-type StockResponse struct {
-	C float64 `json:"c"` // `c` is the current price
+package order
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+type Orders struct{
+	UserId string
+	OrderId string
+	Symbol string
+	PricePerStock decimal.Decimal
+	Quantity int32
+	TotalPrice decimal.Decimal
+	OrderType string
+	OrderStatus string
+	AllowPreMarket bool
+	AllowAfterHours bool
+	TimeInForce string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrderRequest is what every API version turns a place order call into.
+type OrderRequest struct{
+	UserId string
+	Tier string
+	Symbol string
+	Side string
+	Quantity int32
+	TimeInForce string
+	AllowPreMarket bool
+	AllowAfterHours bool
+}
+
+// OrderHistoryQuery selects a page of a user's orders, zero values don't filter. From is
+// inclusive and To exclusive.
+type OrderHistoryQuery struct{
+	UserId string
+	Status string
+	Symbol string
+	Side string
+	From time.Time
+	To time.Time
+	Descending bool
+	PageSize int
+	PageToken string
+}
+
+// OrderPage is one page of order history, NextPageToken is empty on the last page.
+type OrderPage struct{
+	Orders []*mysql.Orders
+	NextPageToken string
+}
+
+type StockResponse struct {
+	C decimal.Decimal `json:"c"` // `c` is the current price
+	T int64   `json:"t"` // `t` is the quote unix timestamp
+}
+
+type AlphaVantageResponse struct {
+	GlobalQuote struct {
+		Price string `json:"05. price"`
+	} `json:"Global Quote"`
+}
+
+// StockQuote is a price along with the provider it came from and when it was observed.
+type StockQuote struct {
+	Price     decimal.Decimal `json:"price"`
+	Source    string  `json:"source"`
+	Timestamp int64   `json:"timestamp"`
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package order
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+const (
+	SORT_ASC  = "asc"
+	SORT_DESC = "desc"
+)
+
+// orderCursor is the position of the last order of a page, the next page starts right
+// after it in (created_at, order_id) order.
+type orderCursor struct {
+	CreatedAt int64  `json:"t"`
+	OrderId   string `json:"id"`
+	Desc      bool   `json:"d"`
+}
+
+// encodePageToken makes the token clients send back for the page after order. Clients
+// should treat it as opaque.
+func encodePageToken(createdAt time.Time, orderId string, desc bool) string {
+	data, _ := json.Marshal(&orderCursor{
+		CreatedAt: createdAt.UnixNano(),
+		OrderId:   orderId,
+		Desc:      desc,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken refuses tokens which don't decode, and tokens from a listing sorted the
+// other way since the cursor would point the wrong way.
+func decodePageToken(token string, desc bool) (*orderCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalidField("pageToken", "page token is not valid")
+	}
+	var cursor orderCursor
+	if err = json.Unmarshal(data, &cursor); err != nil || cursor.OrderId == "" {
+		return nil, invalidField("pageToken", "page token is not valid")
+	}
+	if cursor.Desc != desc {
+		return nil, invalidField("pageToken", "page token is for a different sort order")
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,27 @@
+package order
+
+import (
+	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
+	orderv2 "github.com/tanmaygupta069/order-service-go/generated/order/v2"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+)
+
+// MethodPolicies is the access policy of every OrderService method, enforced by the auth interceptor.
+var MethodPolicies = map[string]string{
+	OrderPb.OrderService_PlaceOrder_FullMethodName:      auth.POLICY_USER,
+	OrderPb.OrderService_CancelOrder_FullMethodName:     auth.POLICY_USER,
+	OrderPb.OrderService_GetOrderHistory_FullMethodName: auth.POLICY_USER,
+	OrderPb.OrderService_GetCurrentPrice_FullMethodName: auth.POLICY_USER,
+	OrderPb.OrderService_GetOrder_FullMethodName:        auth.POLICY_USER,
+	OrderPb.OrderService_GetOrderEvents_FullMethodName:  auth.POLICY_USER,
+	OrderPb.OrderService_SearchSymbols_FullMethodName:   auth.POLICY_USER,
+	OrderPb.OrderService_GetMarketStatus_FullMethodName: auth.POLICY_PUBLIC,
+	OrderPb.OrderService_CompleteOrder_FullMethodName:   auth.POLICY_OPERATOR,
+	OrderPb.OrderService_HaltTrading_FullMethodName:     auth.POLICY_OPERATOR,
+	OrderPb.OrderService_ResumeTrading_FullMethodName:   auth.POLICY_OPERATOR,
+
+	orderv2.OrderService_PlaceOrder_FullMethodName:  auth.POLICY_USER,
+	orderv2.OrderService_CancelOrder_FullMethodName: auth.POLICY_USER,
+	orderv2.OrderService_ListOrders_FullMethodName:  auth.POLICY_USER,
+	orderv2.OrderService_GetQuote_FullMethodName:    auth.POLICY_USER,
+}
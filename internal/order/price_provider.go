@@ -0,0 +1,145 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PROVIDER_FINNHUB       = "finnhub"
+	PROVIDER_ALPHA_VANTAGE = "alphavantage"
+	PROVIDER_SIMULATED     = "simulated"
+)
+
+type PriceProvider interface {
+	Name() string
+	GetQuote(ctx context.Context, symbol string) (*StockQuote, error)
+}
+
+// NewPriceProviders builds the providers in the order they should be consulted,
+// skipping names it doesn't know about.
+func NewPriceProviders(names []string) []PriceProvider {
+	providers := make([]PriceProvider, 0)
+	for _, name := range names {
+		switch name {
+		case PROVIDER_FINNHUB:
+			providers = append(providers, &FinnhubProvider{apiKey: cfg.StockApiKey})
+		case PROVIDER_ALPHA_VANTAGE:
+			providers = append(providers, &AlphaVantageProvider{apiKey: cfg.PriceConfig.AlphaVantageApiKey})
+		case PROVIDER_SIMULATED:
+			fmt.Println("simulated price provider enabled, orders may fill at made up prices")
+			providers = append(providers, &SimulatedProvider{})
+		default:
+			fmt.Printf("unknown price provider %s, skipping\n", name)
+		}
+	}
+	return providers
+}
+
+type FinnhubProvider struct {
+	apiKey string
+}
+
+func (p *FinnhubProvider) Name() string {
+	return PROVIDER_FINNHUB
+}
+
+func (p *FinnhubProvider) GetQuote(ctx context.Context, symbol string) (*StockQuote, error) {
+	url := fmt.Sprintf("https://finnhub.io/api/v1/quote?symbol=%s&token=%s", symbol, p.apiKey)
+	body, err := getBody(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	var stockResp StockResponse
+	if err = json.Unmarshal(body, &stockResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling finnhub response : %v", err)
+	}
+	if !stockResp.C.IsPositive() {
+		return nil, fmt.Errorf("finnhub has no price for %s", symbol)
+	}
+	timestamp := stockResp.T
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	return &StockQuote{
+		Price:     stockResp.C,
+		Source:    p.Name(),
+		Timestamp: timestamp,
+	}, nil
+}
+
+type AlphaVantageProvider struct {
+	apiKey string
+}
+
+func (p *AlphaVantageProvider) Name() string {
+	return PROVIDER_ALPHA_VANTAGE
+}
+
+func (p *AlphaVantageProvider) GetQuote(ctx context.Context, symbol string) (*StockQuote, error) {
+	url := fmt.Sprintf("https://www.alphavantage.co/query?function=GLOBAL_QUOTE&symbol=%s&apikey=%s", symbol, p.apiKey)
+	body, err := getBody(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	var stockResp AlphaVantageResponse
+	if err = json.Unmarshal(body, &stockResp); err != nil {
+		return nil, fmt.Errorf("error unmarshalling alphavantage response : %v", err)
+	}
+	price, err := decimal.NewFromString(stockResp.GlobalQuote.Price)
+	if err != nil || !price.IsPositive() {
+		return nil, fmt.Errorf("alphavantage has no price for %s", symbol)
+	}
+	return &StockQuote{
+		Price:     price,
+		Source:    p.Name(),
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// SimulatedProvider makes up a price, it is meant as the last resort for local setups
+// where no upstream is reachable. It is never a default, PRICE_PROVIDERS has to name it.
+type SimulatedProvider struct{}
+
+func (p *SimulatedProvider) Name() string {
+	return PROVIDER_SIMULATED
+}
+
+func (p *SimulatedProvider) GetQuote(ctx context.Context, symbol string) (*StockQuote, error) {
+	randomFloat := 20 + rand.Float64()*(500-20)
+	return &StockQuote{
+		Price:     decimal.NewFromFloat(randomFloat).Truncate(2),
+		Source:    p.Name(),
+		Timestamp: time.Now().Unix(),
+	}, nil
+}
+
+// getBody gives up on the provider after PRICE_PROVIDER_TIMEOUT_MS so the next one can
+// still answer within the caller's deadline.
+func getBody(ctx context.Context, url string) ([]byte, error) {
+	if cfg.TimeoutConfig.PriceProviderMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(cfg.TimeoutConfig.PriceProviderMs)*time.Millisecond)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from price provider", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -1,120 +1,274 @@
-package order
-
-import (
-	"errors"
-	"fmt"
-
-	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
-	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
-	"gorm.io/gorm"
-	// "gorm.io/gorm"
-)
-
-type OrderRepository interface {
-	PlaceOrder(order *Orders) (*Orders, error)
-	CacheStockPrice(symbol, price string, exp int) error
-	GetCachedStockPrice(symbol string) (string, error)
-	DeleteOrder(orderId string) error
-	GetOrder(orderId string) (*mysql.Orders, error)
-	GetOrders(userId string) ([]*mysql.Orders, error)
-	UpdateOrderStatus(order *mysql.Orders,status string) (*mysql.Orders,error)
-	GetRandomPlacedOrder() (*mysql.Orders, error)
-}
-
-type OrderRepositoryImp struct {
-	mysql       *mysql.SqlServiceImplementation[mysql.Orders]
-	redisClient Redis.RedisInterface
-}
-
-func NewOrderRepository() OrderRepository {
-	return &OrderRepositoryImp{
-		mysql:       mysql.NewSqlClient[mysql.Orders](),
-		redisClient: Redis.NewRedisClient(),
-	}
-}
-
-func (db *OrderRepositoryImp) PlaceOrder(order *Orders) (*Orders, error) {
-	err := db.mysql.Insert(&mysql.Orders{
-		OrderId:       order.OrderId,
-		UserId:        order.UserId,
-		Symbol:        order.Symbol,
-		PricePerStock: order.PricePerStock,
-		Quantity:      order.Quantity,
-		TotalPrice:    order.TotalPrice,
-		OrderType:     order.OrderType,
-		OrderStatus: order.OrderStatus,
-	})
-	if err != nil {
-		fmt.Printf("error in placing order repo")
-		return nil, err
-	}
-	return order, nil
-}
-
-func (db *OrderRepositoryImp) CacheStockPrice(symbol, price string, exp int) error {
-	return db.redisClient.Set(symbol, price, exp)
-}
-
-func (db *OrderRepositoryImp) GetCachedStockPrice(symbol string) (string, error) {
-	price, err := db.redisClient.Get(symbol)
-	if err != nil {
-		return "", fmt.Errorf("key not found in cache")
-	}
-	return price, nil
-}
-
-func (db *OrderRepositoryImp) DeleteOrder(orderId string) error {
-	filter:=map[string]interface{}{
-		"order_id":orderId,
-	}
-	return db.mysql.Delete(filter)
-}
-
-func (db *OrderRepositoryImp) GetOrder(orderId string) (*mysql.Orders, error) {
-	order, err := db.mysql.GetOne(map[string]interface{}{
-		"order_id":orderId,
-	})
-	if errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, fmt.Errorf("no record corrosponding to orderId : %s", orderId)
-	}
-	return order, nil
-}
-
-func (db *OrderRepositoryImp) GetOrders(userId string) ([]*mysql.Orders, error) {
-	orders, err := db.mysql.GetAll(map[string]interface{}{
-		"user_id":userId,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	result := make([]*mysql.Orders, len(orders))
-	for i := range orders {
-		result[i] = &orders[i]
-	}
-
-	return result, nil
-}
-
-func (db *OrderRepositoryImp)UpdateOrderStatus(order *mysql.Orders,status string) (*mysql.Orders,error){
-	valid,_ := AllowedTransitions[order.OrderStatus][status]
-	if !valid{
-		return nil,fmt.Errorf("invalid state change from %s to %s",order.OrderStatus,status)
-	}
-	order.OrderStatus=status
-	err:=db.mysql.Update(order)
-	if err!=nil{
-		return nil,err
-	}
-	return order,nil
-}
-
-func (r *OrderRepositoryImp) GetRandomPlacedOrder() (*mysql.Orders, error) {
-    order,err:=r.mysql.GetOneRandomly(map[string]interface{}{
-		"order_status":"placed",
-	})
-	if err!=nil{
-		return nil,err
-	}
-	return order,nil
-}
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"gorm.io/gorm"
+	// "gorm.io/gorm"
+)
+
+type OrderRepository interface {
+	PlaceOrder(ctx context.Context, order *Orders) (*Orders, error)
+	CacheStockPrice(ctx context.Context, symbol, price string, exp int) error
+	GetCachedStockPrice(ctx context.Context, symbol string) (string, error)
+	DeleteOrder(ctx context.Context, orderId string) error
+	GetOrder(ctx context.Context, orderId string) (*mysql.Orders, error)
+	GetOrders(ctx context.Context, userId string) ([]*mysql.Orders, error)
+	GetOrderPage(ctx context.Context, query *OrderHistoryQuery, after *orderCursor, limit int) ([]*mysql.Orders, error)
+	UpdateOrderStatus(ctx context.Context, order *mysql.Orders,status string,actor string,reason string) (*mysql.Orders,error)
+	GetRandomPlacedOrder(ctx context.Context) (*mysql.Orders, error)
+	GetPlacedOrders(ctx context.Context, afterOrderId string, limit int) ([]*mysql.Orders, error)
+	GetOpenQuantity(ctx context.Context, userId string, symbol string, side string) (int32, error)
+	GetOrderEvents(ctx context.Context, orderId string) ([]*mysql.OrderEvents, error)
+}
+
+type OrderRepositoryImp struct {
+	mysql       *mysql.SqlServiceImplementation[mysql.Orders]
+	events      *mysql.SqlServiceImplementation[mysql.OrderEvents]
+	redisClient Redis.RedisInterface
+}
+
+// NewOrderRepository is backed by MySQL and redis, or by the shared in-memory repository
+// when REPOSITORY_BACKEND is memory.
+func NewOrderRepository() OrderRepository {
+	if cfg != nil && cfg.StorageConfig.RepositoryBackend == config.BACKEND_MEMORY {
+		return getMemoryOrderRepository()
+	}
+	return &OrderRepositoryImp{
+		mysql:       mysql.NewSqlClient[mysql.Orders](),
+		events:      mysql.NewSqlClient[mysql.OrderEvents](),
+		redisClient: Redis.NewRedisClient(),
+	}
+}
+
+// NewSqlOrderRepository returns a repository of its own on the database d instead of the
+// shared connection.
+func NewSqlOrderRepository(d *gorm.DB, cache Redis.RedisInterface) *OrderRepositoryImp {
+	return &OrderRepositoryImp{
+		mysql:       mysql.NewSqlClientFor[mysql.Orders](d),
+		events:      mysql.NewSqlClientFor[mysql.OrderEvents](d),
+		redisClient: cache,
+	}
+}
+
+// PlaceOrder inserts the order along with its first event, the one placing it.
+func (db *OrderRepositoryImp) PlaceOrder(ctx context.Context, order *Orders) (*Orders, error) {
+	row := &mysql.Orders{
+		OrderId:       order.OrderId,
+		UserId:        order.UserId,
+		Symbol:        order.Symbol,
+		PricePerStock: order.PricePerStock,
+		Quantity:      order.Quantity,
+		TotalPrice:    order.TotalPrice,
+		OrderType:     order.OrderType,
+		OrderStatus: order.OrderStatus,
+		AllowPreMarket: order.AllowPreMarket,
+		AllowAfterHours: order.AllowAfterHours,
+		TimeInForce: order.TimeInForce,
+	}
+	err := db.mysql.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := db.mysql.WithTx(tx).Insert(ctx, row); err != nil {
+			return err
+		}
+		return db.events.WithTx(tx).Insert(ctx, &mysql.OrderEvents{
+			OrderId:  row.OrderId,
+			ToStatus: row.OrderStatus,
+			Actor:    row.UserId,
+			Reason:   EVENT_REASON_PLACED,
+		})
+	})
+	if err != nil {
+		fmt.Printf("error in placing order repo")
+		return nil, err
+	}
+	order.CreatedAt = row.CreatedAt
+	order.UpdatedAt = row.UpdatedAt
+	return order, nil
+}
+
+func (db *OrderRepositoryImp) CacheStockPrice(ctx context.Context, symbol, price string, exp int) error {
+	return db.redisClient.Set(ctx, symbol, price, exp)
+}
+
+func (db *OrderRepositoryImp) GetCachedStockPrice(ctx context.Context, symbol string) (string, error) {
+	price, err := db.redisClient.Get(ctx, symbol)
+	if err != nil {
+		return "", fmt.Errorf("key not found in cache")
+	}
+	return price, nil
+}
+
+func (db *OrderRepositoryImp) DeleteOrder(ctx context.Context, orderId string) error {
+	return db.mysql.Delete(ctx, mysql.Where(mysql.Eq("order_id",orderId)))
+}
+
+func (db *OrderRepositoryImp) GetOrder(ctx context.Context, orderId string) (*mysql.Orders, error) {
+	order, err := db.mysql.GetOne(ctx, mysql.Where(mysql.Eq("order_id",orderId)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrderNotFound.Wrap(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (db *OrderRepositoryImp) GetOrders(ctx context.Context, userId string) ([]*mysql.Orders, error) {
+	orders, err := db.mysql.GetAll(ctx, mysql.Where(mysql.Eq("user_id",userId)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*mysql.Orders, len(orders))
+	for i := range orders {
+		result[i] = &orders[i]
+	}
+
+	return result, nil
+}
+
+// GetOrderPage returns up to limit orders matching query which come after the cursor, in
+// (created_at, order_id) order.
+func (db *OrderRepositoryImp) GetOrderPage(ctx context.Context, query *OrderHistoryQuery, after *orderCursor, limit int) ([]*mysql.Orders, error) {
+	q := mysql.Where(mysql.Eq("user_id", query.UserId))
+	if query.Status != "" {
+		q.Where(mysql.Eq("order_status", query.Status))
+	}
+	if query.Symbol != "" {
+		q.Where(mysql.Eq("symbol", query.Symbol))
+	}
+	if query.Side != "" {
+		q.Where(mysql.Eq("order_type", query.Side))
+	}
+	if !query.From.IsZero() {
+		q.Where(mysql.Gte("created_at", query.From))
+	}
+	if !query.To.IsZero() {
+		q.Where(mysql.Lt("created_at", query.To))
+	}
+	if after != nil {
+		keyset := []string{"created_at", "order_id"}
+		if query.Descending {
+			q.Where(mysql.RowLt(keyset, time.Unix(0, after.CreatedAt), after.OrderId))
+		} else {
+			q.Where(mysql.RowGt(keyset, time.Unix(0, after.CreatedAt), after.OrderId))
+		}
+	}
+	q.OrderBy("created_at", query.Descending).OrderBy("order_id", query.Descending).Limit(limit)
+
+	orders, err := db.mysql.GetAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mysql.Orders, len(orders))
+	for i := range orders {
+		result[i] = &orders[i]
+	}
+	return result, nil
+}
+
+// UpdateOrderStatus moves order to status and records who did it and why in the order's
+// events, in the same transaction as the update.
+func (db *OrderRepositoryImp)UpdateOrderStatus(ctx context.Context, order *mysql.Orders,status string,actor string,reason string) (*mysql.Orders,error){
+	event,err:=changeStatus(order,status,actor,reason)
+	if err!=nil{
+		return nil,err
+	}
+	err=db.mysql.Transaction(ctx, func(tx *gorm.DB) error {
+		if err := db.mysql.WithTx(tx).Update(ctx, order); err != nil {
+			return err
+		}
+		return db.events.WithTx(tx).Insert(ctx, event)
+	})
+	if err!=nil{
+		return nil,err
+	}
+	return order,nil
+}
+
+// changeStatus checks that order may move to status, moves it and returns the event
+// recording the change, which every repository stores along with the order.
+func changeStatus(order *mysql.Orders,status string,actor string,reason string)(*mysql.OrderEvents,error){
+	valid,_ := AllowedTransitions[order.OrderStatus][status]
+	if !valid{
+		return nil,ErrInvalidTransition.Withf("invalid state change from %s to %s",order.OrderStatus,status)
+	}
+	event:=&mysql.OrderEvents{
+		OrderId:    order.OrderId,
+		FromStatus: order.OrderStatus,
+		ToStatus:   status,
+		Actor:      actor,
+		Reason:     reason,
+	}
+	now:=time.Now()
+	order.OrderStatus=status
+	if status==STATUS_COMPLETED && order.CompletedAt==nil{
+		order.CompletedAt=&now
+	}
+	if status==STATUS_CANCELLED && order.CancelledAt==nil{
+		order.CancelledAt=&now
+	}
+	return event,nil
+}
+
+// GetOrderEvents returns the events of orderId oldest first.
+func (db *OrderRepositoryImp)GetOrderEvents(ctx context.Context, orderId string)([]*mysql.OrderEvents,error){
+	events,err:=db.events.GetAll(ctx, mysql.Where(mysql.Eq("order_id",orderId)).OrderBy("id",false))
+	if err!=nil{
+		return nil,err
+	}
+	result:=make([]*mysql.OrderEvents,len(events))
+	for i := range events {
+		result[i] = &events[i]
+	}
+	return result,nil
+}
+
+func (r *OrderRepositoryImp) GetRandomPlacedOrder(ctx context.Context) (*mysql.Orders, error) {
+    // order ids are random uuids, so a fresh one is a fair starting point
+    order,err:=r.mysql.GetOneRandomly(ctx, mysql.Where(mysql.Eq("order_status",STATUS_PLACED)), "order_id", uuid.NewString())
+	if err!=nil{
+		return nil,err
+	}
+	return order,nil
+}
+
+// GetPlacedOrders returns up to limit placed orders whose id comes after afterOrderId, in
+// order id order, so the status index can walk all of them a page at a time.
+func (r *OrderRepositoryImp) GetPlacedOrders(ctx context.Context, afterOrderId string, limit int) ([]*mysql.Orders, error) {
+	q := mysql.Where(mysql.Eq("order_status", STATUS_PLACED), mysql.Gt("order_id", afterOrderId)).OrderBy("order_id", false).Limit(limit)
+	orders, err := r.mysql.GetAll(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mysql.Orders, len(orders))
+	for i := range orders {
+		result[i] = &orders[i]
+	}
+	return result, nil
+}
+
+// GetOpenQuantity adds up the quantity of the user's placed orders in symbol on side.
+func (r *OrderRepositoryImp) GetOpenQuantity(ctx context.Context, userId string, symbol string, side string) (int32, error) {
+	orders, err := r.mysql.GetAll(ctx, mysql.Where(
+		mysql.Eq("user_id", userId),
+		mysql.Eq("order_status", STATUS_PLACED),
+		mysql.Eq("symbol", symbol),
+		mysql.Eq("order_type", side),
+	))
+	if err != nil {
+		return 0, err
+	}
+	var quantity int32
+	for _, order := range orders {
+		quantity += order.Quantity
+	}
+	return quantity, nil
+}
\ No newline at end of file
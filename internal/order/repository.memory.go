@@ -0,0 +1,282 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// MemoryOrderRepository keeps orders and their events in the process. It hands out copies,
+// so like the SQL repository a caller only changes what is stored by calling it. Times are
+// kept to the millisecond like the datetime(3) columns.
+type MemoryOrderRepository struct {
+	mu          sync.RWMutex
+	orders      map[string]mysql.Orders
+	events      []mysql.OrderEvents
+	redisClient Redis.RedisInterface
+}
+
+var sharedOrderRepository *MemoryOrderRepository
+
+var memoryOnce sync.Once
+
+// NewMemoryOrderRepository returns an empty repository of its own which caches prices in
+// cache, NewOrderRepository hands out one shared repository when REPOSITORY_BACKEND is
+// memory.
+func NewMemoryOrderRepository(cache Redis.RedisInterface) *MemoryOrderRepository {
+	return &MemoryOrderRepository{
+		orders:      make(map[string]mysql.Orders),
+		events:      make([]mysql.OrderEvents, 0),
+		redisClient: cache,
+	}
+}
+
+func getMemoryOrderRepository() *MemoryOrderRepository {
+	memoryOnce.Do(func() {
+		sharedOrderRepository = NewMemoryOrderRepository(Redis.NewRedisClient())
+	})
+	return sharedOrderRepository
+}
+
+// PlaceOrder stores the order along with its first event, the one placing it.
+func (db *MemoryOrderRepository) PlaceOrder(ctx context.Context, order *Orders) (*Orders, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if _, ok := db.orders[order.OrderId]; ok {
+		return nil, fmt.Errorf("order %s already exists : %w", order.OrderId, gorm.ErrDuplicatedKey)
+	}
+	timeInForce := order.TimeInForce
+	if timeInForce == "" {
+		timeInForce = TIME_IN_FORCE_DAY
+	}
+	now := memoryNow()
+	db.orders[order.OrderId] = mysql.Orders{
+		OrderId:         order.OrderId,
+		UserId:          order.UserId,
+		Symbol:          order.Symbol,
+		PricePerStock:   order.PricePerStock,
+		Quantity:        order.Quantity,
+		TotalPrice:      order.TotalPrice,
+		OrderType:       order.OrderType,
+		OrderStatus:     order.OrderStatus,
+		AllowPreMarket:  order.AllowPreMarket,
+		AllowAfterHours: order.AllowAfterHours,
+		TimeInForce:     timeInForce,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	db.appendEvent(&mysql.OrderEvents{
+		OrderId:  order.OrderId,
+		ToStatus: order.OrderStatus,
+		Actor:    order.UserId,
+		Reason:   EVENT_REASON_PLACED,
+	}, now)
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	return order, nil
+}
+
+func (db *MemoryOrderRepository) CacheStockPrice(ctx context.Context, symbol, price string, exp int) error {
+	return db.redisClient.Set(ctx, symbol, price, exp)
+}
+
+func (db *MemoryOrderRepository) GetCachedStockPrice(ctx context.Context, symbol string) (string, error) {
+	price, err := db.redisClient.Get(ctx, symbol)
+	if err != nil {
+		return "", fmt.Errorf("key not found in cache")
+	}
+	return price, nil
+}
+
+// DeleteOrder leaves the order's events, deleting an order that doesn't exist is no error.
+func (db *MemoryOrderRepository) DeleteOrder(ctx context.Context, orderId string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	delete(db.orders, orderId)
+	return nil
+}
+
+func (db *MemoryOrderRepository) GetOrder(ctx context.Context, orderId string) (*mysql.Orders, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	order, ok := db.orders[orderId]
+	if !ok {
+		return nil, ErrOrderNotFound.Wrap(gorm.ErrRecordNotFound)
+	}
+	return &order, nil
+}
+
+// GetOrders returns the user's orders in (created_at, order_id) order.
+func (db *MemoryOrderRepository) GetOrders(ctx context.Context, userId string) ([]*mysql.Orders, error) {
+	return db.GetOrderPage(ctx, &OrderHistoryQuery{UserId: userId}, nil, 0)
+}
+
+// GetOrderPage returns up to limit orders matching query which come after the cursor, in
+// (created_at, order_id) order. A limit of 0 is no limit.
+func (db *MemoryOrderRepository) GetOrderPage(ctx context.Context, query *OrderHistoryQuery, after *orderCursor, limit int) ([]*mysql.Orders, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make([]*mysql.Orders, 0)
+	for _, order := range db.orders {
+		if !matchesHistoryQuery(&order, query) {
+			continue
+		}
+		if after != nil {
+			position := compareOrderPosition(&order, after.CreatedAt, after.OrderId)
+			if (query.Descending && position >= 0) || (!query.Descending && position <= 0) {
+				continue
+			}
+		}
+		order := order
+		result = append(result, &order)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		position := compareOrderPosition(result[i], result[j].CreatedAt.UnixNano(), result[j].OrderId)
+		if query.Descending {
+			return position > 0
+		}
+		return position < 0
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// UpdateOrderStatus moves order to status and records who did it and why in the order's
+// events, both under one lock.
+func (db *MemoryOrderRepository) UpdateOrderStatus(ctx context.Context, order *mysql.Orders, status string, actor string, reason string) (*mysql.Orders, error) {
+	event, err := changeStatus(order, status, actor, reason)
+	if err != nil {
+		return nil, err
+	}
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	now := memoryNow()
+	order.UpdatedAt = now
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = now
+	}
+	db.orders[order.OrderId] = *order
+	db.appendEvent(event, now)
+	return order, nil
+}
+
+// GetRandomPlacedOrder returns gorm.ErrRecordNotFound when no order is placed, like the
+// SQL repository.
+func (db *MemoryOrderRepository) GetRandomPlacedOrder(ctx context.Context) (*mysql.Orders, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	placed := make([]mysql.Orders, 0)
+	for _, order := range db.orders {
+		if order.OrderStatus == STATUS_PLACED {
+			placed = append(placed, order)
+		}
+	}
+	if len(placed) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	order := placed[rand.Intn(len(placed))]
+	return &order, nil
+}
+
+// GetPlacedOrders returns up to limit placed orders whose id comes after afterOrderId, in
+// order id order.
+func (db *MemoryOrderRepository) GetPlacedOrders(ctx context.Context, afterOrderId string, limit int) ([]*mysql.Orders, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make([]*mysql.Orders, 0)
+	for _, order := range db.orders {
+		if order.OrderStatus == STATUS_PLACED && order.OrderId > afterOrderId {
+			order := order
+			result = append(result, &order)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OrderId < result[j].OrderId
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// GetOpenQuantity adds up the quantity of the user's placed orders in symbol on side.
+func (db *MemoryOrderRepository) GetOpenQuantity(ctx context.Context, userId string, symbol string, side string) (int32, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	var quantity int32
+	for _, order := range db.orders {
+		if order.UserId == userId && order.OrderStatus == STATUS_PLACED && order.Symbol == symbol && order.OrderType == side {
+			quantity += order.Quantity
+		}
+	}
+	return quantity, nil
+}
+
+// GetOrderEvents returns the events of orderId oldest first.
+func (db *MemoryOrderRepository) GetOrderEvents(ctx context.Context, orderId string) ([]*mysql.OrderEvents, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	result := make([]*mysql.OrderEvents, 0)
+	for _, event := range db.events {
+		if event.OrderId == orderId {
+			event := event
+			result = append(result, &event)
+		}
+	}
+	return result, nil
+}
+
+// appendEvent numbers event like the auto increment id would. Callers hold mu.
+func (db *MemoryOrderRepository) appendEvent(event *mysql.OrderEvents, now time.Time) {
+	event.Id = uint64(len(db.events) + 1)
+	event.CreatedAt = now
+	db.events = append(db.events, *event)
+}
+
+func matchesHistoryQuery(order *mysql.Orders, query *OrderHistoryQuery) bool {
+	switch {
+	case order.UserId != query.UserId:
+		return false
+	case query.Status != "" && order.OrderStatus != query.Status:
+		return false
+	case query.Symbol != "" && order.Symbol != query.Symbol:
+		return false
+	case query.Side != "" && order.OrderType != query.Side:
+		return false
+	case !query.From.IsZero() && order.CreatedAt.Before(query.From):
+		return false
+	case !query.To.IsZero() && !order.CreatedAt.Before(query.To):
+		return false
+	}
+	return true
+}
+
+// compareOrderPosition compares order with the position (createdAt, orderId) in
+// (created_at, order_id) order, returning -1, 0 or 1.
+func compareOrderPosition(order *mysql.Orders, createdAt int64, orderId string) int {
+	switch t := order.CreatedAt.UnixNano(); {
+	case t < createdAt:
+		return -1
+	case t > createdAt:
+		return 1
+	case order.OrderId < orderId:
+		return -1
+	case order.OrderId > orderId:
+		return 1
+	}
+	return 0
+}
+
+// memoryNow is the current time at the precision the SQL backend stores.
+func memoryNow() time.Time {
+	return time.Now().Truncate(time.Millisecond)
+}
@@ -0,0 +1,237 @@
+package order
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/holding"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// benchOrders is how many orders the benchmarks seed, go test -bench . -bench.orders
+// 1000000 runs them against a million.
+var benchOrders = flag.Int("bench.orders", 100000, "orders seeded for the repository benchmarks")
+
+// Budgets are per call, a lookup over that means an index isn't being used.
+const (
+	orderPageBudget       = 5 * time.Millisecond
+	randomPlacedBudget    = 5 * time.Millisecond
+	holdingLookupBudget   = 2 * time.Millisecond
+	benchOrdersPerUser    = 1000
+	benchSymbols          = 50
+	benchHoldingsPerUser  = 20
+	benchSeedBatch        = 1000
+	benchPlacedOneInEvery = 10
+)
+
+var benchData struct {
+	once  sync.Once
+	db    *gorm.DB
+	users int
+	err   error
+}
+
+// benchDatabase seeds an in-memory SQLite database through the dialect layer once per
+// run, each user has benchOrdersPerUser orders spread over a year.
+func benchDatabase(b *testing.B) (*gorm.DB, int) {
+	b.Helper()
+	benchData.once.Do(func() {
+		benchData.db, benchData.users, benchData.err = seedBenchDatabase(*benchOrders)
+	})
+	if benchData.err != nil {
+		b.Fatalf("error seeding benchmark database : %v", benchData.err)
+	}
+	return benchData.db, benchData.users
+}
+
+func seedBenchDatabase(orders int) (*gorm.DB, int, error) {
+	ctx := context.Background()
+	d, err := mysql.OpenDatabase(ctx, config.MySqlConfig{
+		Driver:     config.DRIVER_SQLITE,
+		SqlitePath: ":memory:",
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	// batches of a thousand rows are slow queries to gorm's logger
+	seed := d.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	users := (orders + benchOrdersPerUser - 1) / benchOrdersPerUser
+	start := time.Now().AddDate(-1, 0, 0)
+	statuses := []string{STATUS_COMPLETED, STATUS_CANCELLED}
+	random := rand.New(rand.NewSource(1))
+
+	batch := make([]mysql.Orders, 0, benchSeedBatch)
+	for i := 0; i < orders; i++ {
+		status := statuses[random.Intn(len(statuses))]
+		if i%benchPlacedOneInEvery == 0 {
+			status = STATUS_PLACED
+		}
+		price := decimal.NewFromInt(int64(10 + random.Intn(500)))
+		quantity := int32(1 + random.Intn(100))
+		batch = append(batch, mysql.Orders{
+			OrderId:       uuid.NewString(),
+			UserId:        benchUser(i % users),
+			Symbol:        benchSymbol(random.Intn(benchSymbols)),
+			PricePerStock: price,
+			Quantity:      quantity,
+			TotalPrice:    price.Mul(decimal.NewFromInt32(quantity)),
+			OrderType:     SIDE_BUY,
+			OrderStatus:   status,
+			TimeInForce:   TIME_IN_FORCE_GTC,
+			CreatedAt:     start.Add(time.Duration(random.Int63n(int64(365 * 24 * time.Hour)))),
+		})
+		if len(batch) == benchSeedBatch || i == orders-1 {
+			if err := seed.Create(&batch).Error; err != nil {
+				return nil, 0, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	holdings := make([]mysql.Holdings, 0, benchSeedBatch)
+	for user := 0; user < users; user++ {
+		for symbol := 0; symbol < benchHoldingsPerUser; symbol++ {
+			holdings = append(holdings, mysql.Holdings{
+				UserId:     benchUser(user),
+				Symbol:     benchSymbol(symbol),
+				Quantity:   10,
+				TotalPrice: decimal.NewFromInt(1000),
+			})
+		}
+		if len(holdings) >= benchSeedBatch || user == users-1 {
+			if err := seed.Create(&holdings).Error; err != nil {
+				return nil, 0, err
+			}
+			holdings = holdings[:0]
+		}
+	}
+	return d, users, nil
+}
+
+func benchUser(i int) string {
+	return fmt.Sprintf("user-%06d", i)
+}
+
+func benchSymbol(i int) string {
+	return fmt.Sprintf("SYM%02d", i)
+}
+
+// checkBudget fails the benchmark when a call took longer than budget on average.
+func checkBudget(b *testing.B, budget time.Duration) {
+	b.Helper()
+	if b.N == 0 {
+		return
+	}
+	if perOp := b.Elapsed() / time.Duration(b.N); perOp > budget {
+		b.Fatalf("%s per call, budget is %s", perOp, budget)
+	}
+}
+
+func BenchmarkGetOrderPage(b *testing.B) {
+	d, users := benchDatabase(b)
+	repo := NewSqlOrderRepository(d, Redis.NewMemoryRedis())
+	ctx := context.Background()
+
+	queries := map[string]func(user string) *OrderHistoryQuery{
+		"newest first": func(user string) *OrderHistoryQuery {
+			return &OrderHistoryQuery{UserId: user, Descending: true}
+		},
+		"by status": func(user string) *OrderHistoryQuery {
+			return &OrderHistoryQuery{UserId: user, Status: STATUS_PLACED, Descending: true}
+		},
+		"date range": func(user string) *OrderHistoryQuery {
+			return &OrderHistoryQuery{UserId: user, From: time.Now().AddDate(0, -3, 0), To: time.Now()}
+		},
+	}
+	for name, query := range queries {
+		b.Run(name, func(b *testing.B) {
+			random := rand.New(rand.NewSource(2))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				q := query(benchUser(random.Intn(users)))
+				page, err := repo.GetOrderPage(ctx, q, nil, 50)
+				if err != nil {
+					b.Fatalf("GetOrderPage() error = %v", err)
+				}
+				// the page after it, the way a client walks the history
+				if len(page) > 0 {
+					last := page[len(page)-1]
+					after := &orderCursor{CreatedAt: last.CreatedAt.UnixNano(), OrderId: last.OrderId, Desc: q.Descending}
+					if _, err := repo.GetOrderPage(ctx, q, after, 50); err != nil {
+						b.Fatalf("GetOrderPage() error = %v", err)
+					}
+				}
+			}
+			b.StopTimer()
+			// two pages a call
+			checkBudget(b, 2*orderPageBudget)
+		})
+	}
+}
+
+func BenchmarkGetRandomPlacedOrder(b *testing.B) {
+	d, _ := benchDatabase(b)
+	repo := NewSqlOrderRepository(d, Redis.NewMemoryRedis())
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		order, err := repo.GetRandomPlacedOrder(ctx)
+		if err != nil {
+			b.Fatalf("GetRandomPlacedOrder() error = %v", err)
+		}
+		if order.OrderStatus != STATUS_PLACED {
+			b.Fatalf("GetRandomPlacedOrder() = %s order", order.OrderStatus)
+		}
+	}
+	b.StopTimer()
+	checkBudget(b, randomPlacedBudget)
+}
+
+func BenchmarkGetHolding(b *testing.B) {
+	d, users := benchDatabase(b)
+	repo := holding.NewSqlHoldingRepository(d)
+	ctx := context.Background()
+	random := rand.New(rand.NewSource(3))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lookup := &mysql.Holdings{UserId: benchUser(random.Intn(users)), Symbol: benchSymbol(random.Intn(benchHoldingsPerUser))}
+		if _, err := repo.GetHolding(ctx, lookup); err != nil {
+			b.Fatalf("GetHolding() error = %v", err)
+		}
+	}
+	b.StopTimer()
+	checkBudget(b, holdingLookupBudget)
+}
+
+func BenchmarkGetHoldings(b *testing.B) {
+	d, users := benchDatabase(b)
+	repo := holding.NewSqlHoldingRepository(d)
+	ctx := context.Background()
+	random := rand.New(rand.NewSource(4))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		holdings, err := repo.GetHoldings(ctx, &mysql.Holdings{UserId: benchUser(random.Intn(users))})
+		if err != nil {
+			b.Fatalf("GetHoldings() error = %v", err)
+		}
+		if len(holdings) != benchHoldingsPerUser {
+			b.Fatalf("GetHoldings() = %d holdings, want %d", len(holdings), benchHoldingsPerUser)
+		}
+	}
+	b.StopTimer()
+	checkBudget(b, holdingLookupBudget)
+}
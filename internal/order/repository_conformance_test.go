@@ -0,0 +1,327 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"gorm.io/gorm"
+)
+
+// openTestDatabase opens a migrated in-memory SQLite database private to the test.
+func openTestDatabase(t testing.TB) *gorm.DB {
+	t.Helper()
+	d, err := mysql.OpenDatabase(context.Background(), config.MySqlConfig{
+		Driver:     config.DRIVER_SQLITE,
+		SqlitePath: ":memory:",
+	})
+	if err != nil {
+		t.Fatalf("error opening test database : %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := d.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return d
+}
+
+// forEachOrderRepository runs test against an empty repository of every backend, they
+// have to behave the same for the service.
+func forEachOrderRepository(t *testing.T, test func(t *testing.T, repo OrderRepository)) {
+	backends := map[string]func(t *testing.T) OrderRepository{
+		config.BACKEND_MEMORY: func(t *testing.T) OrderRepository {
+			return NewMemoryOrderRepository(Redis.NewMemoryRedis())
+		},
+		config.BACKEND_SQL: func(t *testing.T) OrderRepository {
+			return NewSqlOrderRepository(openTestDatabase(t), Redis.NewMemoryRedis())
+		},
+	}
+	for name, newRepo := range backends {
+		t.Run(name, func(t *testing.T) {
+			test(t, newRepo(t))
+		})
+	}
+}
+
+func testOrder(orderId string, userId string, symbol string, side string) *Orders {
+	return &Orders{
+		OrderId:       orderId,
+		UserId:        userId,
+		Symbol:        symbol,
+		PricePerStock: decimal.RequireFromString("10.25"),
+		Quantity:      4,
+		TotalPrice:    decimal.RequireFromString("41"),
+		OrderType:     side,
+		OrderStatus:   STATUS_PLACED,
+	}
+}
+
+func placeTestOrder(t *testing.T, repo OrderRepository, order *Orders) *Orders {
+	t.Helper()
+	placed, err := repo.PlaceOrder(context.Background(), order)
+	if err != nil {
+		t.Fatalf("PlaceOrder(%s) error = %v", order.OrderId, err)
+	}
+	return placed
+}
+
+func setTestOrderStatus(t *testing.T, repo OrderRepository, orderId string, status string) *mysql.Orders {
+	t.Helper()
+	order, err := repo.GetOrder(context.Background(), orderId)
+	if err != nil {
+		t.Fatalf("GetOrder(%s) error = %v", orderId, err)
+	}
+	updated, err := repo.UpdateOrderStatus(context.Background(), order, status, ACTOR_SYSTEM, "test")
+	if err != nil {
+		t.Fatalf("UpdateOrderStatus(%s, %s) error = %v", orderId, status, err)
+	}
+	return updated
+}
+
+func ids(orders []*mysql.Orders) []string {
+	result := make([]string, 0, len(orders))
+	for _, order := range orders {
+		result = append(result, order.OrderId)
+	}
+	return result
+}
+
+func TestOrderRepositoryPlaceAndGet(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		ctx := context.Background()
+		placed := placeTestOrder(t, repo, testOrder("o1", "u1", "AAPL", SIDE_BUY))
+		if placed.CreatedAt.IsZero() {
+			t.Errorf("PlaceOrder() left CreatedAt unset")
+		}
+
+		got, err := repo.GetOrder(ctx, "o1")
+		if err != nil {
+			t.Fatalf("GetOrder() error = %v", err)
+		}
+		if got.UserId != "u1" || got.Symbol != "AAPL" || got.OrderType != SIDE_BUY || got.Quantity != 4 || got.OrderStatus != STATUS_PLACED {
+			t.Errorf("GetOrder() = %+v, want the placed order", got)
+		}
+		if !got.PricePerStock.Equal(decimal.RequireFromString("10.25")) || !got.TotalPrice.Equal(decimal.NewFromInt(41)) {
+			t.Errorf("GetOrder() amounts = %s %s, want 10.25 41", got.PricePerStock, got.TotalPrice)
+		}
+		if got.TimeInForce != TIME_IN_FORCE_DAY {
+			t.Errorf("GetOrder() time in force = %q, want the DAY default", got.TimeInForce)
+		}
+		if !got.CreatedAt.Truncate(time.Millisecond).Equal(placed.CreatedAt.Truncate(time.Millisecond)) {
+			t.Errorf("GetOrder() created at = %s, PlaceOrder() returned %s", got.CreatedAt, placed.CreatedAt)
+		}
+
+		if _, err := repo.PlaceOrder(ctx, testOrder("o1", "u2", "MSFT", SIDE_BUY)); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Errorf("PlaceOrder() of an existing id error = %v, want gorm.ErrDuplicatedKey", err)
+		}
+		if _, err := repo.GetOrder(ctx, "missing"); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("GetOrder() of a missing order error = %v, want ErrOrderNotFound", err)
+		}
+	})
+}
+
+func TestOrderRepositoryStatusChangesAndEvents(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		ctx := context.Background()
+		placeTestOrder(t, repo, testOrder("o1", "u1", "AAPL", SIDE_BUY))
+
+		completed := setTestOrderStatus(t, repo, "o1", STATUS_COMPLETED)
+		if completed.CompletedAt == nil {
+			t.Errorf("UpdateOrderStatus() left CompletedAt unset")
+		}
+		got, err := repo.GetOrder(ctx, "o1")
+		if err != nil {
+			t.Fatalf("GetOrder() error = %v", err)
+		}
+		if got.OrderStatus != STATUS_COMPLETED || got.CompletedAt == nil || got.CancelledAt != nil {
+			t.Errorf("GetOrder() = %s completed %v cancelled %v, want completed", got.OrderStatus, got.CompletedAt, got.CancelledAt)
+		}
+
+		if _, err := repo.UpdateOrderStatus(ctx, got, STATUS_PLACED, "u1", "test"); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("UpdateOrderStatus() from completed error = %v, want ErrInvalidTransition", err)
+		}
+		if got, _ := repo.GetOrder(ctx, "o1"); got.OrderStatus != STATUS_COMPLETED {
+			t.Errorf("refused status change was stored, status is %s", got.OrderStatus)
+		}
+
+		events, err := repo.GetOrderEvents(ctx, "o1")
+		if err != nil {
+			t.Fatalf("GetOrderEvents() error = %v", err)
+		}
+		if len(events) != 2 {
+			t.Fatalf("GetOrderEvents() = %d events, want 2", len(events))
+		}
+		if e := events[0]; e.FromStatus != "" || e.ToStatus != STATUS_PLACED || e.Actor != "u1" || e.Reason != EVENT_REASON_PLACED {
+			t.Errorf("first event = %+v, want the placement by u1", e)
+		}
+		if e := events[1]; e.FromStatus != STATUS_PLACED || e.ToStatus != STATUS_COMPLETED || e.Actor != ACTOR_SYSTEM || e.Reason != "test" {
+			t.Errorf("second event = %+v, want the completion", e)
+		}
+		if events[0].Id >= events[1].Id || events[0].CreatedAt.IsZero() {
+			t.Errorf("events are not numbered and timed in order: %+v %+v", events[0], events[1])
+		}
+		if events, _ := repo.GetOrderEvents(ctx, "missing"); len(events) != 0 {
+			t.Errorf("GetOrderEvents() of a missing order = %d events, want none", len(events))
+		}
+	})
+}
+
+func TestOrderRepositoryOrderPages(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		ctx := context.Background()
+		orders := []*Orders{
+			testOrder("o1", "u1", "AAPL", SIDE_BUY),
+			testOrder("o2", "u1", "MSFT", SIDE_SELL),
+			testOrder("o3", "u1", "AAPL", SIDE_SELL),
+			testOrder("o4", "u2", "AAPL", SIDE_BUY),
+			testOrder("o5", "u1", "AAPL", SIDE_BUY),
+		}
+		created := make(map[string]time.Time)
+		for _, order := range orders {
+			created[order.OrderId] = placeTestOrder(t, repo, order).CreatedAt
+			// apart in created_at even at the millisecond precision of MySQL
+			time.Sleep(2 * time.Millisecond)
+		}
+		setTestOrderStatus(t, repo, "o3", STATUS_CANCELLED)
+
+		all, err := repo.GetOrders(ctx, "u1")
+		if err != nil {
+			t.Fatalf("GetOrders() error = %v", err)
+		}
+		got := ids(all)
+		sort.Strings(got)
+		if want := []string{"o1", "o2", "o3", "o5"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetOrders() = %v, want %v", got, want)
+		}
+
+		tests := []struct {
+			name  string
+			query OrderHistoryQuery
+			want  []string
+		}{
+			{"oldest first", OrderHistoryQuery{UserId: "u1"}, []string{"o1", "o2", "o3", "o5"}},
+			{"newest first", OrderHistoryQuery{UserId: "u1", Descending: true}, []string{"o5", "o3", "o2", "o1"}},
+			{"by status", OrderHistoryQuery{UserId: "u1", Status: STATUS_PLACED}, []string{"o1", "o2", "o5"}},
+			{"by symbol", OrderHistoryQuery{UserId: "u1", Symbol: "AAPL"}, []string{"o1", "o3", "o5"}},
+			{"by side", OrderHistoryQuery{UserId: "u1", Side: SIDE_SELL}, []string{"o2", "o3"}},
+			{"from is inclusive, to exclusive", OrderHistoryQuery{UserId: "u1", From: created["o2"], To: created["o5"]}, []string{"o2", "o3"}},
+			{"other user", OrderHistoryQuery{UserId: "u3"}, []string{}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// two at a time, the way GetOrderHistory walks the pages
+				got := make([]string, 0)
+				var after *orderCursor
+				for page := 0; page < 10; page++ {
+					orders, err := repo.GetOrderPage(ctx, &tt.query, after, 2)
+					if err != nil {
+						t.Fatalf("GetOrderPage() error = %v", err)
+					}
+					got = append(got, ids(orders)...)
+					if len(orders) < 2 {
+						break
+					}
+					last := orders[len(orders)-1]
+					after = &orderCursor{CreatedAt: last.CreatedAt.UnixNano(), OrderId: last.OrderId, Desc: tt.query.Descending}
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("pages = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestOrderRepositoryPlacedOrders(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		ctx := context.Background()
+		if _, err := repo.GetRandomPlacedOrder(ctx); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("GetRandomPlacedOrder() without placed orders error = %v, want gorm.ErrRecordNotFound", err)
+		}
+
+		for _, id := range []string{"o1", "o2", "o3", "o4", "o5"} {
+			placeTestOrder(t, repo, testOrder(id, "u1", "AAPL", SIDE_BUY))
+		}
+		setTestOrderStatus(t, repo, "o2", STATUS_COMPLETED)
+		setTestOrderStatus(t, repo, "o4", STATUS_CANCELLED)
+
+		for i := 0; i < 20; i++ {
+			order, err := repo.GetRandomPlacedOrder(ctx)
+			if err != nil {
+				t.Fatalf("GetRandomPlacedOrder() error = %v", err)
+			}
+			if order.OrderStatus != STATUS_PLACED {
+				t.Fatalf("GetRandomPlacedOrder() = %s, a %s order", order.OrderId, order.OrderStatus)
+			}
+		}
+
+		first, err := repo.GetPlacedOrders(ctx, "", 2)
+		if err != nil {
+			t.Fatalf("GetPlacedOrders() error = %v", err)
+		}
+		rest, err := repo.GetPlacedOrders(ctx, first[len(first)-1].OrderId, 2)
+		if err != nil {
+			t.Fatalf("GetPlacedOrders() error = %v", err)
+		}
+		if got, want := append(ids(first), ids(rest)...), []string{"o1", "o3", "o5"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetPlacedOrders() pages = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestOrderRepositoryOpenQuantity(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		placeTestOrder(t, repo, testOrder("o1", "u1", "AAPL", SIDE_BUY))
+		placeTestOrder(t, repo, testOrder("o2", "u1", "AAPL", SIDE_BUY))
+		placeTestOrder(t, repo, testOrder("o3", "u1", "AAPL", SIDE_BUY))
+		placeTestOrder(t, repo, testOrder("o4", "u1", "AAPL", SIDE_SELL))
+		placeTestOrder(t, repo, testOrder("o5", "u1", "MSFT", SIDE_BUY))
+		placeTestOrder(t, repo, testOrder("o6", "u2", "AAPL", SIDE_BUY))
+		setTestOrderStatus(t, repo, "o3", STATUS_COMPLETED)
+
+		quantity, err := repo.GetOpenQuantity(context.Background(), "u1", "AAPL", SIDE_BUY)
+		if err != nil {
+			t.Fatalf("GetOpenQuantity() error = %v", err)
+		}
+		if quantity != 8 {
+			t.Errorf("GetOpenQuantity() = %d, want the 8 of o1 and o2", quantity)
+		}
+		if quantity, _ := repo.GetOpenQuantity(context.Background(), "u3", "AAPL", SIDE_BUY); quantity != 0 {
+			t.Errorf("GetOpenQuantity() without orders = %d, want 0", quantity)
+		}
+	})
+}
+
+func TestOrderRepositoryDeleteAndPriceCache(t *testing.T) {
+	forEachOrderRepository(t, func(t *testing.T, repo OrderRepository) {
+		ctx := context.Background()
+		placeTestOrder(t, repo, testOrder("o1", "u1", "AAPL", SIDE_BUY))
+		if err := repo.DeleteOrder(ctx, "o1"); err != nil {
+			t.Fatalf("DeleteOrder() error = %v", err)
+		}
+		if _, err := repo.GetOrder(ctx, "o1"); !errors.Is(err, ErrOrderNotFound) {
+			t.Errorf("GetOrder() of a deleted order error = %v, want ErrOrderNotFound", err)
+		}
+		if err := repo.DeleteOrder(ctx, "o1"); err != nil {
+			t.Errorf("DeleteOrder() of a missing order error = %v, want none", err)
+		}
+
+		if _, err := repo.GetCachedStockPrice(ctx, "AAPL"); err == nil {
+			t.Errorf("GetCachedStockPrice() of an uncached symbol error = nil")
+		}
+		if err := repo.CacheStockPrice(ctx, "AAPL", "189.5", 60); err != nil {
+			t.Fatalf("CacheStockPrice() error = %v", err)
+		}
+		if price, err := repo.GetCachedStockPrice(ctx, "AAPL"); err != nil || price != "189.5" {
+			t.Errorf("GetCachedStockPrice() = %q, %v, want 189.5", price, err)
+		}
+	})
+}
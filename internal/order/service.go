@@ -1,178 +1,495 @@
-package order
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"math"
-	"math/rand"
-	"net/http"
-	"strconv"
-	"github.com/google/uuid"
-	"github.com/tanmaygupta069/order-service-go/config"
-	"github.com/tanmaygupta069/order-service-go/internal/holding"
-	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
-)
-
-var cfg, _ = config.GetConfig()
-
-type OrderService interface {
-	PlaceOrder(order *Orders) (*Orders, error)
-	DeleteOrder(orderId string) (*mysql.Orders, error)
-	GenerateOrderId() string
-	GetStockPrice(symbol string) (float64, error)
-	IDORCheck(userid, orderId string) (bool, error)
-	GetOrderHistory(userId string) ([]*mysql.Orders, error)
-	CancelOrder(orderId string) (*mysql.Orders, error)
-	CompleteRandomOrders() error
-	CompleteOrder(orderId string)(*mysql.Orders,error)
-	CheckStockQuantity(userId string,symbol string,quantity int32)(bool,error)
-}
-
-type OrderServiceImp struct {
-	repo OrderRepository
-	holdingService holding.HoldingService
-}
-
-func NewOrderService() OrderService {
-	return &OrderServiceImp{
-		repo: NewOrderRepository(),
-		holdingService: holding.NewHoldingService(),
-	}
-}
-
-func (r *OrderServiceImp) PlaceOrder(order *Orders) (*Orders, error) {
-	return r.repo.PlaceOrder(order)
-}
-
-func (r *OrderServiceImp) GenerateOrderId() string {
-	orderID := uuid.New()
-	return orderID.String()
-}
-
-func (r *OrderServiceImp) GetStockPrice(symbol string) (float64, error) {
-
-	if price, err := r.repo.GetCachedStockPrice(symbol); price != "" || err == nil {
-		return strconv.ParseFloat(price, 64)
-	}
-	url := fmt.Sprintf("https://finnhub.io/api/v1/quote?symbol=%s&token=%s", symbol, cfg.StockApiKey)
-	resp, err := http.Get(url)
-	if err != nil {
-		fmt.Println("Error:", err)
-		return 0.00, err
-	}
-	defer resp.Body.Close()
-
-	body, _ := io.ReadAll(resp.Body)
-	var stockResp StockResponse
-	err = json.Unmarshal(body, &stockResp)
-	if err != nil {
-		fmt.Println("Error unmarshalling JSON:", err)
-		return 0.00, err
-	}
-	if stockResp.C <= 0.00 {
-		randomFloat := 20 + rand.Float64()*(500-20)
-		randomFloat = float64(int(randomFloat*100)) / 100
-		stockResp.C = randomFloat
-	}
-	simulatedPrice := SimulatePrice(stockResp.C)
-	r.repo.CacheStockPrice(symbol, strconv.FormatFloat(simulatedPrice, 'f', 2, 64), 1)
-	simulatedPrice = math.Round(simulatedPrice*100) / 100
-
-	return simulatedPrice, nil
-}
-
-func (r *OrderServiceImp) DeleteOrder(orderId string) (*mysql.Orders, error) {
-	order, err := r.repo.GetOrder(orderId)
-	if err != nil {
-		return nil, fmt.Errorf("error getting order : %v", err)
-	}
-	if err = r.repo.DeleteOrder(orderId); err != nil {
-		return nil, fmt.Errorf("error deleting order : %v", err)
-	}
-	return order, nil
-}
-
-func (r *OrderServiceImp) IDORCheck(userid, orderId string) (bool, error) {
-	order, err := r.repo.GetOrder(orderId)
-	if err != nil {
-		return false, err
-	}
-	if userid != order.UserId {
-		return false, nil
-	}
-	return true, nil
-}
-
-func (r *OrderServiceImp) GetOrderHistory(userId string) ([]*mysql.Orders, error) {
-	return r.repo.GetOrders(userId)
-}
-
-func (r *OrderServiceImp) CancelOrder(orderId string) (*mysql.Orders, error) {
-	order, err := r.repo.GetOrder(orderId)
-	if err != nil {
-		return nil, err
-	}
-	return r.repo.UpdateOrderStatus(order, "cancelled")
-}
-
-func (r *OrderServiceImp) CompleteRandomOrders() error {
-	order, err := r.repo.GetRandomPlacedOrder()
-	if err != nil {
-		return err
-	}
-	if rand.Intn(2) == 0 {
-		fmt.Printf("Completing order %s\n", order.OrderId)
-		_, err = r.repo.UpdateOrderStatus(order, "completed")
-		if err != nil {
-			return err
-		}
-		holding:=&mysql.Holdings{
-			UserId: order.UserId,
-			Symbol: order.Symbol,
-			Quantity: order.Quantity,
-			TotalPrice: order.TotalPrice,
-		}
-		r.holdingService.UpdateHoldings(holding,order.OrderType)
-	}
-	return nil
-}
-
-func (r *OrderServiceImp)CompleteOrder(orderId string)(*mysql.Orders,error){
-	if r.repo == nil {
-		return nil, fmt.Errorf("repo is nil")
-	}
-	if r.holdingService == nil {
-		return nil, fmt.Errorf("holdingService is nil")
-	}
-
-	order, err := r.repo.GetOrder(orderId)
-	if err != nil {
-		return nil, err
-	}
-	updatedorder,er:=r.repo.UpdateOrderStatus(order, "completed")
-	if er!=nil{
-		return nil,er
-	}
-	if order == nil{
-		return nil,fmt.Errorf("order nil in complete order")
-	}
-	holding:=&mysql.Holdings{
-		UserId: updatedorder.UserId,
-		Symbol: updatedorder.Symbol,
-		Quantity: updatedorder.Quantity,
-		TotalPrice: updatedorder.TotalPrice,
-	}
-	return order,r.holdingService.UpdateHoldings(holding,updatedorder.OrderType)
-}
-
-func (r *OrderServiceImp)CheckStockQuantity(userId string,symbol string,quantity int32)(bool,error){
-	holding,err:=r.holdingService.GetHolding(userId,symbol)
-	if err!=nil{
-		return false,err
-	}
-	if holding.Quantity < quantity{
-		return false,nil
-	}
-	return true,nil
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/holding"
+	"github.com/tanmaygupta069/order-service-go/internal/market"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/money"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"github.com/tanmaygupta069/order-service-go/internal/risk"
+	"github.com/tanmaygupta069/order-service-go/internal/security"
+)
+
+var cfg, _ = config.GetConfig()
+
+type OrderService interface {
+	SubmitOrder(ctx context.Context, req *OrderRequest) (*Orders, error)
+	CancelUserOrder(ctx context.Context, userId string, orderId string) (*mysql.Orders, error)
+	PlaceOrder(ctx context.Context, order *Orders) (*Orders, error)
+	DeleteOrder(ctx context.Context, orderId string) (*mysql.Orders, error)
+	GenerateOrderId() string
+	GetStockPrice(ctx context.Context, symbol string) (*StockQuote, error)
+	IDORCheck(ctx context.Context, userid, orderId string) (bool, error)
+	GetOrderHistory(ctx context.Context, query *OrderHistoryQuery) (*OrderPage, error)
+	CancelOrder(ctx context.Context, orderId string, actor string, reason string) (*mysql.Orders, error)
+	CompleteRandomOrders(ctx context.Context) error
+	ExpireDayOrders(ctx context.Context) (int, error)
+	CompleteOrder(ctx context.Context, orderId string,actor string)(*mysql.Orders,error)
+	GetOrder(ctx context.Context, orderId string)(*mysql.Orders,error)
+	GetUserOrder(ctx context.Context, userId string,orderId string)(*mysql.Orders,error)
+	GetOrderEvents(ctx context.Context, orderId string)([]*mysql.OrderEvents,error)
+	CheckStockQuantity(ctx context.Context, userId string,symbol string,quantity int32)(bool,error)
+	ValidateSymbol(ctx context.Context, symbol string)(*mysql.Securities,error)
+	SearchSymbols(ctx context.Context, query string,limit int)([]*mysql.Securities,error)
+	GetSecurity(ctx context.Context, symbol string)(*mysql.Securities,error)
+	GetMarketStatus(exchange string)*market.MarketStatus
+	CanTradeNow(exchange string,preMarket bool,afterHours bool)bool
+	CheckRisk(ctx context.Context, order *risk.OrderContext)(*risk.Rejection,error)
+	GetActiveHalt(ctx context.Context, symbol string)(*market.Halt,error)
+	ListHalts(ctx context.Context)([]*market.Halt,error)
+	HaltTrading(ctx context.Context, symbol string,reason string,haltedBy string,minutes int)(*market.Halt,error)
+	ResumeTrading(ctx context.Context, symbol string)(bool,error)
+}
+
+type OrderServiceImp struct {
+	repo OrderRepository
+	holdingService holding.HoldingService
+	securityService security.SecurityService
+	calendar market.TradingCalendar
+	riskService risk.RiskService
+	haltService market.HaltService
+	priceProviders []PriceProvider
+}
+
+func NewOrderService() OrderService {
+	return &OrderServiceImp{
+		repo: NewOrderRepository(),
+		holdingService: holding.NewHoldingService(),
+		securityService: security.NewSecurityService(),
+		calendar: market.NewTradingCalendar(),
+		riskService: risk.NewRiskService(),
+		haltService: market.NewHaltService(),
+		priceProviders: NewPriceProviders(cfg.PriceConfig.Providers),
+	}
+}
+
+// SubmitOrder runs every pre-trade check on req and places the order at the current price.
+func (r *OrderServiceImp) SubmitOrder(ctx context.Context, req *OrderRequest) (*Orders, error) {
+	symbol := strings.ToUpper(req.Symbol)
+	if req.Quantity <= 0 {
+		return nil, invalidField("quantity", "quantity must be greater than zero")
+	}
+	timeInForce := req.TimeInForce
+	if timeInForce == "" {
+		timeInForce = TIME_IN_FORCE_DAY
+	}
+	switch timeInForce {
+	case TIME_IN_FORCE_DAY, TIME_IN_FORCE_GTC:
+	case TIME_IN_FORCE_IOC:
+		// nothing cancels an unfilled IOC order yet, it would rest like a GTC one
+		return nil, invalidField("time_in_force", "immediate or cancel orders are not supported yet")
+	default:
+		return nil, invalidField("time_in_force", fmt.Sprintf("unknown time in force %s", timeInForce))
+	}
+
+	security, err := r.ValidateSymbol(ctx, symbol)
+	if err != nil {
+		return nil, symbolError(err)
+	}
+	if req.Quantity%security.LotSize != 0 {
+		return nil, invalidField("quantity", fmt.Sprintf("quantity must be a multiple of the lot size %d", security.LotSize))
+	}
+	if !r.CanTradeNow(security.Exchange, req.AllowPreMarket, req.AllowAfterHours) && !cfg.MarketConfig.QueueOutsideHours {
+		return nil, ErrMarketClosed.Withf("market is closed for %s", security.Exchange)
+	}
+
+	if req.Side == SIDE_SELL {
+		ok, err := r.CheckStockQuantity(ctx, req.UserId, symbol, req.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, holding.ErrInsufficientHoldings
+		}
+	}
+	quote, err := r.GetStockPrice(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	halt, err := r.GetActiveHalt(ctx, security.Symbol)
+	if err != nil {
+		// without knowing whether the symbol is halted the order is refused
+		return nil, ErrHaltsUnavailable.Withf("unable to check trading halts for %s : %v", security.Symbol, err).Wrap(err)
+	}
+	if halt != nil {
+		return nil, ErrTradingHalted.Withf("trading in %s is halted : %s", halt.Symbol, halt.Reason)
+	}
+	openQuantity, err := r.repo.GetOpenQuantity(ctx, req.UserId, symbol, req.Side)
+	if err != nil {
+		return nil, err
+	}
+	rejection, err := r.CheckRisk(ctx, &risk.OrderContext{
+		UserId:       req.UserId,
+		Tier:         req.Tier,
+		Symbol:       symbol,
+		OrderType:    req.Side,
+		Quantity:     req.Quantity,
+		Price:        quote.Price.InexactFloat64(),
+		OpenQuantity: openQuantity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if rejection != nil {
+		return nil, apperror.Rejected(rejection.Reason, rejection.Message)
+	}
+
+	return r.PlaceOrder(ctx, &Orders{
+		OrderId:         r.GenerateOrderId(),
+		UserId:          req.UserId,
+		Symbol:          symbol,
+		PricePerStock:   quote.Price,
+		Quantity:        req.Quantity,
+		TotalPrice:      money.Total(quote.Price, req.Quantity, currencyOrDefault(security.Currency)),
+		OrderType:       req.Side,
+		OrderStatus:     STATUS_PLACED,
+		AllowPreMarket:  req.AllowPreMarket,
+		AllowAfterHours: req.AllowAfterHours,
+		TimeInForce:     timeInForce,
+	})
+}
+
+// CancelUserOrder cancels orderId on behalf of userId, refusing orders of other users.
+func (r *OrderServiceImp) CancelUserOrder(ctx context.Context, userId string, orderId string) (*mysql.Orders, error) {
+	valid, err := r.IDORCheck(ctx, userId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrNotOrderOwner
+	}
+	return r.CancelOrder(ctx, orderId, userId, EVENT_REASON_USER_CANCELLED)
+}
+
+func (r *OrderServiceImp) PlaceOrder(ctx context.Context, order *Orders) (*Orders, error) {
+	res, err := r.repo.PlaceOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	err = r.riskService.RecordOrder(ctx, &risk.OrderContext{
+		UserId:    order.UserId,
+		Symbol:    order.Symbol,
+		OrderType: order.OrderType,
+		Quantity:  order.Quantity,
+		Price:     order.PricePerStock.InexactFloat64(),
+	})
+	if err != nil {
+		fmt.Printf("error recording order %s for risk : %v\n", order.OrderId, err)
+	}
+	return res, nil
+}
+
+func (r *OrderServiceImp) GenerateOrderId() string {
+	orderID := uuid.New()
+	return orderID.String()
+}
+
+func (r *OrderServiceImp) GetStockPrice(ctx context.Context, symbol string) (*StockQuote, error) {
+
+	if cached, err := r.repo.GetCachedStockPrice(ctx, symbol); err == nil {
+		var quote StockQuote
+		if err = json.Unmarshal([]byte(cached), &quote); err == nil {
+			return &quote, nil
+		}
+	}
+	quote, err := r.fetchQuote(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	// the breaker watches the market, not the simulated move on top of it
+	if halt, err := r.haltService.ObservePrice(ctx, symbol, quote.Price.InexactFloat64()); err != nil {
+		log.Printf("error running circuit breaker for %s : %v", symbol, err)
+	} else if halt != nil {
+		log.Printf("circuit breaker halted %s : %s", symbol, halt.Reason)
+	}
+	quote.Price = money.Round(SimulatePrice(quote.Price), r.currencyOf(ctx, symbol))
+	if data, err := json.Marshal(quote); err == nil {
+		r.repo.CacheStockPrice(ctx, symbol, string(data), 1)
+	}
+	return quote, nil
+}
+
+// fetchQuote asks the providers in order and returns the first price it gets. When
+// cross checking is on, the quote is compared with the next provider that answers and
+// rejected if the two are too far apart.
+func (r *OrderServiceImp) fetchQuote(ctx context.Context, symbol string) (*StockQuote, error) {
+	var primary *StockQuote
+	var lastErr error
+	for _, provider := range r.priceProviders {
+		quote, err := provider.GetQuote(ctx, symbol)
+		if err != nil {
+			fmt.Printf("price provider %s failed for %s : %v\n", provider.Name(), symbol, err)
+			lastErr = err
+			continue
+		}
+		if primary == nil {
+			primary = quote
+			if !cfg.PriceConfig.CrossCheck {
+				return primary, nil
+			}
+			continue
+		}
+		// providers never return a price at or below zero, the check only keeps Div safe
+		if !quote.Price.IsPositive() {
+			continue
+		}
+		divergence := primary.Price.Sub(quote.Price).Abs().Div(quote.Price).Mul(decimal.NewFromInt(100))
+		if divergence.GreaterThan(decimal.NewFromFloat(cfg.PriceConfig.MaxDivergencePct)) {
+			return nil, ErrPriceUnavailable.Withf("price for %s from %s (%s) and %s (%s) diverge by %s%%", symbol, primary.Source, primary.Price.StringFixed(2), quote.Source, quote.Price.StringFixed(2), divergence.StringFixed(2))
+		}
+		return primary, nil
+	}
+	if primary != nil {
+		fmt.Printf("no second provider available to cross check %s, using %s\n", symbol, primary.Source)
+		return primary, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no price providers configured")
+	}
+	return nil, ErrPriceUnavailable.Withf("unable to get price for %s : %v", symbol, lastErr).Wrap(lastErr)
+}
+
+func (r *OrderServiceImp) DeleteOrder(ctx context.Context, orderId string) (*mysql.Orders, error) {
+	order, err := r.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, fmt.Errorf("error getting order : %v", err)
+	}
+	if err = r.repo.DeleteOrder(ctx, orderId); err != nil {
+		return nil, fmt.Errorf("error deleting order : %v", err)
+	}
+	return order, nil
+}
+
+func (r *OrderServiceImp) IDORCheck(ctx context.Context, userid, orderId string) (bool, error) {
+	order, err := r.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return false, err
+	}
+	if userid != order.UserId {
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetOrderHistory returns one page of the user's orders. It asks the repository for one
+// order more than the page size to know whether there is a next page.
+func (r *OrderServiceImp) GetOrderHistory(ctx context.Context, query *OrderHistoryQuery) (*OrderPage, error) {
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = cfg.OrderHistoryConfig.DefaultPageSize
+	}
+	if pageSize > cfg.OrderHistoryConfig.MaxPageSize {
+		pageSize = cfg.OrderHistoryConfig.MaxPageSize
+	}
+	var after *orderCursor
+	if query.PageToken != "" {
+		cursor, err := decodePageToken(query.PageToken, query.Descending)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+
+	orders, err := r.repo.GetOrderPage(ctx, query, after, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &OrderPage{
+		Orders: orders,
+	}
+	if len(orders) > pageSize {
+		page.Orders = orders[:pageSize]
+		last := page.Orders[pageSize-1]
+		page.NextPageToken = encodePageToken(last.CreatedAt, last.OrderId, query.Descending)
+	}
+	return page, nil
+}
+
+func (r *OrderServiceImp) CancelOrder(ctx context.Context, orderId string, actor string, reason string) (*mysql.Orders, error) {
+	order, err := r.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	return r.repo.UpdateOrderStatus(ctx, order, "cancelled", actor, reason)
+}
+
+func (r *OrderServiceImp) CompleteRandomOrders(ctx context.Context) error {
+	order, err := r.repo.GetRandomPlacedOrder(ctx)
+	if err != nil {
+		return err
+	}
+	if r.isExpired(ctx, order) {
+		_, err = r.expireOrder(ctx, order)
+		return err
+	}
+	if !r.canFill(ctx, order) {
+		fmt.Printf("market closed or halted for order %s, leaving it queued\n", order.OrderId)
+		return nil
+	}
+	if rand.Intn(2) == 0 {
+		fmt.Printf("Completing order %s\n", order.OrderId)
+		_, err = r.repo.UpdateOrderStatus(ctx, order, "completed", ACTOR_SYSTEM, EVENT_REASON_SIMULATED_FILL)
+		if err != nil {
+			return err
+		}
+		holding:=&mysql.Holdings{
+			UserId: order.UserId,
+			Symbol: order.Symbol,
+			Quantity: order.Quantity,
+			TotalPrice: order.TotalPrice,
+		}
+		r.holdingService.UpdateHoldings(ctx, holding,order.OrderType)
+	}
+	return nil
+}
+
+func (r *OrderServiceImp)CompleteOrder(ctx context.Context, orderId string,actor string)(*mysql.Orders,error){
+	if r.repo == nil {
+		return nil, fmt.Errorf("repo is nil")
+	}
+	if r.holdingService == nil {
+		return nil, fmt.Errorf("holdingService is nil")
+	}
+
+	order, err := r.repo.GetOrder(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if r.isExpired(ctx, order) {
+		if _, err = r.expireOrder(ctx, order); err != nil {
+			return nil, err
+		}
+		return nil, ErrOrderExpired
+	}
+	if order.OrderStatus == STATUS_PLACED && !r.canFill(ctx, order) {
+		return nil, ErrMarketClosed.Withf("market is closed or halted for %s, order can't be filled now", order.Symbol)
+	}
+	updatedorder,er:=r.repo.UpdateOrderStatus(ctx, order, "completed", actor, EVENT_REASON_FILLED)
+	if er!=nil{
+		return nil,er
+	}
+	if order == nil{
+		return nil,fmt.Errorf("order nil in complete order")
+	}
+	holding:=&mysql.Holdings{
+		UserId: updatedorder.UserId,
+		Symbol: updatedorder.Symbol,
+		Quantity: updatedorder.Quantity,
+		TotalPrice: updatedorder.TotalPrice,
+	}
+	return order,r.holdingService.UpdateHoldings(ctx, holding,updatedorder.OrderType)
+}
+
+func (r *OrderServiceImp)GetOrder(ctx context.Context, orderId string)(*mysql.Orders,error){
+	return r.repo.GetOrder(ctx, orderId)
+}
+
+// GetUserOrder is GetOrder for the owner of the order. Orders of other users are reported
+// as not found so order ids can't be probed for existence.
+func (r *OrderServiceImp)GetUserOrder(ctx context.Context, userId string,orderId string)(*mysql.Orders,error){
+	order,err:=r.repo.GetOrder(ctx, orderId)
+	if err!=nil{
+		return nil,err
+	}
+	if order.UserId!=userId{
+		return nil,ErrOrderNotFound
+	}
+	return order,nil
+}
+
+func (r *OrderServiceImp)GetOrderEvents(ctx context.Context, orderId string)([]*mysql.OrderEvents,error){
+	return r.repo.GetOrderEvents(ctx, orderId)
+}
+
+func (r *OrderServiceImp)CheckStockQuantity(ctx context.Context, userId string,symbol string,quantity int32)(bool,error){
+	existing,err:=r.holdingService.GetHolding(ctx, userId,symbol)
+	if errors.Is(err,holding.ErrHoldingNotFound){
+		return false,nil
+	}
+	if err!=nil{
+		return false,err
+	}
+	if existing.Quantity < quantity{
+		return false,nil
+	}
+	return true,nil
+}
+
+func (r *OrderServiceImp)ValidateSymbol(ctx context.Context, symbol string)(*mysql.Securities,error){
+	return r.securityService.ValidateSymbol(ctx, symbol)
+}
+
+func (r *OrderServiceImp)SearchSymbols(ctx context.Context, query string,limit int)([]*mysql.Securities,error){
+	return r.securityService.SearchSymbols(ctx, query,limit)
+}
+
+func (r *OrderServiceImp)GetSecurity(ctx context.Context, symbol string)(*mysql.Securities,error){
+	return r.securityService.GetSecurity(ctx, symbol)
+}
+
+// currencyOf is the currency symbol is quoted in, DEFAULT_CURRENCY when the security
+// master doesn't know.
+func (r *OrderServiceImp)currencyOf(ctx context.Context, symbol string)string{
+	security,err:=r.GetSecurity(ctx, symbol)
+	if err!=nil{
+		return DEFAULT_CURRENCY
+	}
+	return currencyOrDefault(security.Currency)
+}
+
+func (r *OrderServiceImp)GetMarketStatus(exchange string)*market.MarketStatus{
+	return r.calendar.Status(exchange,time.Now())
+}
+
+func (r *OrderServiceImp)CanTradeNow(exchange string,preMarket bool,afterHours bool)bool{
+	return r.calendar.CanTrade(exchange,time.Now(),preMarket,afterHours)
+}
+
+// canFill reports whether the order's symbol isn't halted and its exchange is in a
+// session the order is allowed to trade in. An order whose halt can't be read isn't filled.
+func (r *OrderServiceImp)canFill(ctx context.Context, order *mysql.Orders)bool{
+	if halt,err := r.haltService.GetActiveHalt(ctx, order.Symbol);err!=nil || halt!=nil{
+		return false
+	}
+	return r.CanTradeNow(r.exchangeOf(ctx, order.Symbol),order.AllowPreMarket,order.AllowAfterHours)
+}
+
+// exchangeOf is the exchange symbol trades on, empty for the calendar's default when the
+// security master doesn't know.
+func (r *OrderServiceImp)exchangeOf(ctx context.Context, symbol string)string{
+	if security,err := r.securityService.GetSecurity(ctx, symbol);err==nil{
+		return security.Exchange
+	}
+	return ""
+}
+
+func (r *OrderServiceImp)CheckRisk(ctx context.Context, order *risk.OrderContext)(*risk.Rejection,error){
+	return r.riskService.Evaluate(ctx, order)
+}
+
+func (r *OrderServiceImp)GetActiveHalt(ctx context.Context, symbol string)(*market.Halt,error){
+	return r.haltService.GetActiveHalt(ctx, symbol)
+}
+
+func (r *OrderServiceImp)ListHalts(ctx context.Context)([]*market.Halt,error){
+	return r.haltService.ListHalts(ctx)
+}
+
+func (r *OrderServiceImp)HaltTrading(ctx context.Context, symbol string,reason string,haltedBy string,minutes int)(*market.Halt,error){
+	return r.haltService.Halt(ctx, symbol,reason,haltedBy,minutes)
+}
+
+func (r *OrderServiceImp)ResumeTrading(ctx context.Context, symbol string)(bool,error){
+	return r.haltService.Resume(ctx, symbol)
 }
\ No newline at end of file
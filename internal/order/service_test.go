@@ -0,0 +1,431 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/holding"
+	"github.com/tanmaygupta069/order-service-go/internal/market"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/apperror"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+	"github.com/tanmaygupta069/order-service-go/internal/risk"
+	"github.com/tanmaygupta069/order-service-go/internal/security"
+)
+
+func TestMain(m *testing.M) {
+	cfg = &config.Config{
+		OrderHistoryConfig: config.OrderHistoryConfig{DefaultPageSize: 20, MaxPageSize: 100},
+		MarketConfig:       config.MarketConfig{QueueOutsideHours: false},
+		PriceConfig:        config.PriceConfig{Providers: []string{"fixed"}},
+	}
+	os.Exit(m.Run())
+}
+
+type fakeSecurities struct {
+	securities map[string]*mysql.Securities
+}
+
+func (f *fakeSecurities) GetSecurity(ctx context.Context, symbol string) (*mysql.Securities, error) {
+	if security, ok := f.securities[symbol]; ok {
+		return security, nil
+	}
+	return nil, security.ErrUnknownSymbol
+}
+
+func (f *fakeSecurities) ValidateSymbol(ctx context.Context, symbol string) (*mysql.Securities, error) {
+	return f.GetSecurity(ctx, symbol)
+}
+
+func (f *fakeSecurities) SearchSymbols(ctx context.Context, term string, limit int) ([]*mysql.Securities, error) {
+	return nil, nil
+}
+
+func (f *fakeSecurities) LoadFromCSV(ctx context.Context, path string) (int, error) {
+	return 0, nil
+}
+
+// fakeCalendar is open or closed all day, every session ends at sessionEnd.
+type fakeCalendar struct {
+	open       bool
+	sessionEnd time.Time
+}
+
+func (f *fakeCalendar) Session(exchange string, t time.Time) string {
+	if f.open {
+		return market.SESSION_REGULAR
+	}
+	return market.SESSION_CLOSED
+}
+
+func (f *fakeCalendar) Status(exchange string, t time.Time) *market.MarketStatus {
+	return &market.MarketStatus{Exchange: exchange, Session: f.Session(exchange, t), IsOpen: f.open}
+}
+
+func (f *fakeCalendar) CanTrade(exchange string, t time.Time, preMarket bool, afterHours bool) bool {
+	return f.open
+}
+
+func (f *fakeCalendar) SessionEnd(exchange string, t time.Time, afterHours bool) time.Time {
+	return f.sessionEnd
+}
+
+// fakeHalts reports halt and err, and keeps the prices the circuit breaker was shown.
+type fakeHalts struct {
+	halt     *market.Halt
+	err      error
+	observed []float64
+}
+
+func (f *fakeHalts) Halt(ctx context.Context, symbol string, reason string, haltedBy string, minutes int) (*market.Halt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (f *fakeHalts) Resume(ctx context.Context, symbol string) (bool, error) {
+	return false, fmt.Errorf("not supported")
+}
+
+func (f *fakeHalts) GetActiveHalt(ctx context.Context, symbol string) (*market.Halt, error) {
+	return f.halt, f.err
+}
+
+func (f *fakeHalts) ListHalts(ctx context.Context) ([]*market.Halt, error) {
+	return nil, nil
+}
+
+func (f *fakeHalts) ObservePrice(ctx context.Context, symbol string, price float64) (*market.Halt, error) {
+	f.observed = append(f.observed, price)
+	return nil, nil
+}
+
+// fakeRisk keeps the orders it was asked about and rejects them with reject.
+type fakeRisk struct {
+	reject    *risk.Rejection
+	evaluated []*risk.OrderContext
+	recorded  []*risk.OrderContext
+}
+
+func (f *fakeRisk) Evaluate(ctx context.Context, order *risk.OrderContext) (*risk.Rejection, error) {
+	f.evaluated = append(f.evaluated, order)
+	return f.reject, nil
+}
+
+func (f *fakeRisk) RecordOrder(ctx context.Context, order *risk.OrderContext) error {
+	f.recorded = append(f.recorded, order)
+	return nil
+}
+
+type fixedPriceProvider struct {
+	price decimal.Decimal
+}
+
+func (p *fixedPriceProvider) Name() string {
+	return "fixed"
+}
+
+func (p *fixedPriceProvider) GetQuote(ctx context.Context, symbol string) (*StockQuote, error) {
+	return &StockQuote{Price: p.price, Source: p.Name(), Timestamp: time.Now().Unix()}, nil
+}
+
+type testService struct {
+	*OrderServiceImp
+	calendar *fakeCalendar
+	halts    *fakeHalts
+	risk     *fakeRisk
+}
+
+// newTestService is an order service over the memory repositories with AAPL trading at
+// about 100 on an open market.
+func newTestService() *testService {
+	s := &testService{
+		calendar: &fakeCalendar{open: true},
+		halts:    &fakeHalts{},
+		risk:     &fakeRisk{},
+	}
+	s.OrderServiceImp = &OrderServiceImp{
+		repo:           NewMemoryOrderRepository(Redis.NewMemoryRedis()),
+		holdingService: holding.NewHoldingServiceWithRepository(holding.NewMemoryHoldingRepository()),
+		securityService: &fakeSecurities{securities: map[string]*mysql.Securities{
+			"AAPL": {Symbol: "AAPL", Exchange: "NASDAQ", Currency: "USD", LotSize: 1, Tradable: true},
+		}},
+		calendar:       s.calendar,
+		riskService:    s.risk,
+		haltService:    s.halts,
+		priceProviders: []PriceProvider{&fixedPriceProvider{price: decimal.NewFromInt(100)}},
+	}
+	return s
+}
+
+func buyRequest(userId string, quantity int32) *OrderRequest {
+	return &OrderRequest{UserId: userId, Symbol: "aapl", Side: SIDE_BUY, Quantity: quantity}
+}
+
+func (s *testService) submit(t *testing.T, req *OrderRequest) *Orders {
+	t.Helper()
+	order, err := s.SubmitOrder(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SubmitOrder() error = %v", err)
+	}
+	return order
+}
+
+func (s *testService) complete(t *testing.T, orderId string) {
+	t.Helper()
+	if _, err := s.CompleteOrder(context.Background(), orderId, ACTOR_SYSTEM); err != nil {
+		t.Fatalf("CompleteOrder(%s) error = %v", orderId, err)
+	}
+}
+
+func TestSubmitOrderPlacesOrder(t *testing.T) {
+	s := newTestService()
+	order := s.submit(t, buyRequest("u1", 5))
+
+	if order.Symbol != "AAPL" || order.UserId != "u1" || order.Quantity != 5 || order.OrderStatus != STATUS_PLACED || order.TimeInForce != TIME_IN_FORCE_DAY {
+		t.Errorf("SubmitOrder() = %+v, want a placed day order of 5 AAPL", order)
+	}
+	if order.PricePerStock.LessThan(decimal.NewFromInt(99)) || order.PricePerStock.GreaterThan(decimal.NewFromInt(101)) {
+		t.Errorf("price = %s, want the quote of 100 give or take the simulated move", order.PricePerStock)
+	}
+	if !order.TotalPrice.Equal(order.PricePerStock.Mul(decimal.NewFromInt(5))) {
+		t.Errorf("total = %s, want 5 times %s", order.TotalPrice, order.PricePerStock)
+	}
+	if _, err := s.GetOrder(context.Background(), order.OrderId); err != nil {
+		t.Errorf("GetOrder() of the submitted order error = %v", err)
+	}
+	if len(s.risk.recorded) != 1 || s.risk.recorded[0].Quantity != 5 {
+		t.Errorf("risk recorded %+v, want the order", s.risk.recorded)
+	}
+}
+
+func TestSubmitOrderRefusals(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(s *testService)
+		req     *OrderRequest
+		want    error
+	}{
+		{
+			name:    "market closed",
+			prepare: func(s *testService) { s.calendar.open = false },
+			req:     buyRequest("u1", 1),
+			want:    ErrMarketClosed,
+		},
+		{
+			name:    "immediate or cancel",
+			prepare: func(s *testService) {},
+			req:     &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_BUY, Quantity: 1, TimeInForce: TIME_IN_FORCE_IOC},
+			want:    invalidField("time_in_force", ""),
+		},
+		{
+			name:    "immediate or cancel outside hours",
+			prepare: func(s *testService) { s.calendar.open = false; cfg.MarketConfig.QueueOutsideHours = true },
+			req:     &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_BUY, Quantity: 1, TimeInForce: TIME_IN_FORCE_IOC},
+			want:    invalidField("time_in_force", ""),
+		},
+		{
+			name:    "unknown time in force",
+			prepare: func(s *testService) {},
+			req:     &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_BUY, Quantity: 1, TimeInForce: "FOK"},
+			want:    invalidField("time_in_force", ""),
+		},
+		{
+			name:    "halted",
+			prepare: func(s *testService) { s.halts.halt = &market.Halt{Symbol: "AAPL", Reason: "news pending"} },
+			req:     buyRequest("u1", 1),
+			want:    ErrTradingHalted,
+		},
+		{
+			name:    "halts unavailable",
+			prepare: func(s *testService) { s.halts.err = errors.New("redis down") },
+			req:     buyRequest("u1", 1),
+			want:    ErrHaltsUnavailable,
+		},
+		{
+			name:    "risk rejection",
+			prepare: func(s *testService) { s.risk.reject = &risk.Rejection{Reason: "MAX_NOTIONAL", Message: "too big"} },
+			req:     buyRequest("u1", 1),
+			want:    apperror.Rejected("MAX_NOTIONAL", ""),
+		},
+		{
+			name:    "sell without holdings",
+			prepare: func(s *testService) {},
+			req:     &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_SELL, Quantity: 1},
+			want:    holding.ErrInsufficientHoldings,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(queue bool) { cfg.MarketConfig.QueueOutsideHours = queue }(cfg.MarketConfig.QueueOutsideHours)
+			s := newTestService()
+			tt.prepare(s)
+			if _, err := s.SubmitOrder(context.Background(), tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("SubmitOrder() error = %v, want %v", err, tt.want)
+			}
+			if orders, _ := s.repo.GetOrders(context.Background(), "u1"); len(orders) != 0 {
+				t.Errorf("refused order was stored")
+			}
+		})
+	}
+}
+
+func TestSubmitOrderQueuesOutsideHours(t *testing.T) {
+	defer func(queue bool) { cfg.MarketConfig.QueueOutsideHours = queue }(cfg.MarketConfig.QueueOutsideHours)
+	cfg.MarketConfig.QueueOutsideHours = true
+	s := newTestService()
+	s.calendar.open = false
+
+	order := s.submit(t, buyRequest("u1", 1))
+	if _, err := s.CompleteOrder(context.Background(), order.OrderId, ACTOR_SYSTEM); !errors.Is(err, ErrMarketClosed) {
+		t.Errorf("CompleteOrder() on a closed market error = %v, want ErrMarketClosed", err)
+	}
+}
+
+func TestSubmitOrderPassesOpenQuantityToRisk(t *testing.T) {
+	s := newTestService()
+	s.submit(t, buyRequest("u1", 3))
+	s.submit(t, buyRequest("u1", 4))
+	s.submit(t, buyRequest("u2", 10))
+
+	s.submit(t, buyRequest("u1", 1))
+	evaluated := s.risk.evaluated[len(s.risk.evaluated)-1]
+	if evaluated.OpenQuantity != 7 || evaluated.Quantity != 1 || evaluated.Symbol != "AAPL" || evaluated.OrderType != SIDE_BUY {
+		t.Errorf("risk evaluated %+v, want 1 AAPL on top of the 7 open", evaluated)
+	}
+}
+
+func TestCircuitBreakerSeesTheProviderQuote(t *testing.T) {
+	halts := &fakeHalts{}
+	jittered := false
+	for i := 0; i < 20; i++ {
+		// a fresh service each time so the quote isn't served from the cache
+		s := newTestService()
+		s.halts = halts
+		s.haltService = halts
+		s.priceProviders = []PriceProvider{&fixedPriceProvider{price: decimal.RequireFromString("5")}}
+		quote, err := s.GetStockPrice(context.Background(), "AAPL")
+		if err != nil {
+			t.Fatalf("GetStockPrice() error = %v", err)
+		}
+		jittered = jittered || !quote.Price.Equal(decimal.RequireFromString("5"))
+	}
+	if !jittered {
+		t.Fatalf("quotes weren't simulated, the test proves nothing")
+	}
+	// at 5.00 the simulated move alone would be up to 20%, twice the default breaker
+	for _, price := range halts.observed {
+		if price != 5 {
+			t.Fatalf("circuit breaker was shown %v, want the provider quote of 5", halts.observed)
+		}
+	}
+	if len(halts.observed) != 20 {
+		t.Errorf("circuit breaker saw %d quotes, want 20", len(halts.observed))
+	}
+}
+
+func TestCompleteOrderUpdatesHoldings(t *testing.T) {
+	s := newTestService()
+	ctx := context.Background()
+	buy := s.submit(t, buyRequest("u1", 10))
+	s.complete(t, buy.OrderId)
+
+	completed, err := s.GetOrder(ctx, buy.OrderId)
+	if err != nil {
+		t.Fatalf("GetOrder() error = %v", err)
+	}
+	if completed.OrderStatus != STATUS_COMPLETED {
+		t.Errorf("status = %s, want completed", completed.OrderStatus)
+	}
+	held, err := s.holdingService.GetHolding(ctx, "u1", "AAPL")
+	if err != nil {
+		t.Fatalf("GetHolding() error = %v", err)
+	}
+	if held.Quantity != 10 || !held.TotalPrice.Equal(buy.TotalPrice) {
+		t.Errorf("holding = %d for %s, want 10 for %s", held.Quantity, held.TotalPrice, buy.TotalPrice)
+	}
+
+	sell := s.submit(t, &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_SELL, Quantity: 4})
+	s.complete(t, sell.OrderId)
+	held, err = s.holdingService.GetHolding(ctx, "u1", "AAPL")
+	if err != nil {
+		t.Fatalf("GetHolding() error = %v", err)
+	}
+	if held.Quantity != 6 || !held.TotalPrice.Equal(buy.TotalPrice.Sub(sell.TotalPrice)) {
+		t.Errorf("holding after the sell = %d for %s, want 6", held.Quantity, held.TotalPrice)
+	}
+
+	if _, err := s.SubmitOrder(ctx, &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_SELL, Quantity: 7}); !errors.Is(err, holding.ErrInsufficientHoldings) {
+		t.Errorf("SubmitOrder() selling more than held error = %v, want ErrInsufficientHoldings", err)
+	}
+}
+
+func TestDayOrdersExpire(t *testing.T) {
+	s := newTestService()
+	ctx := context.Background()
+	day := s.submit(t, buyRequest("u1", 1))
+	swept := s.submit(t, buyRequest("u1", 1))
+	gtc := s.submit(t, &OrderRequest{UserId: "u1", Symbol: "AAPL", Side: SIDE_BUY, Quantity: 1, TimeInForce: TIME_IN_FORCE_GTC})
+
+	s.calendar.sessionEnd = time.Now().Add(time.Hour)
+	if count, err := s.ExpireDayOrders(ctx); err != nil || count != 0 {
+		t.Errorf("ExpireDayOrders() before the close = %d, %v, want none expired", count, err)
+	}
+
+	s.calendar.sessionEnd = time.Now().Add(-time.Second)
+	if _, err := s.CompleteOrder(ctx, day.OrderId, ACTOR_SYSTEM); !errors.Is(err, ErrOrderExpired) {
+		t.Errorf("CompleteOrder() after the close error = %v, want ErrOrderExpired", err)
+	}
+	count, err := s.ExpireDayOrders(ctx)
+	if err != nil {
+		t.Fatalf("ExpireDayOrders() error = %v", err)
+	}
+	if count != 1 {
+		t.Errorf("ExpireDayOrders() = %d, want only the untouched day order", count)
+	}
+
+	for orderId, want := range map[string]string{day.OrderId: STATUS_CANCELLED, swept.OrderId: STATUS_CANCELLED, gtc.OrderId: STATUS_PLACED} {
+		order, err := s.GetOrder(ctx, orderId)
+		if err != nil {
+			t.Fatalf("GetOrder() error = %v", err)
+		}
+		if order.OrderStatus != want {
+			t.Errorf("order %s is %s, want %s", orderId, order.OrderStatus, want)
+		}
+	}
+	events, _ := s.GetOrderEvents(ctx, swept.OrderId)
+	if last := events[len(events)-1]; last.Actor != ACTOR_SYSTEM || last.Reason != EVENT_REASON_EXPIRED {
+		t.Errorf("last event = %+v, want the expiry", last)
+	}
+	if _, err := s.holdingService.GetHolding(ctx, "u1", "AAPL"); !errors.Is(err, holding.ErrHoldingNotFound) {
+		t.Errorf("expired orders changed the holdings, GetHolding() error = %v", err)
+	}
+}
+
+func TestOrdersOfOtherUsers(t *testing.T) {
+	s := newTestService()
+	ctx := context.Background()
+	order := s.submit(t, buyRequest("u1", 1))
+
+	if _, err := s.CancelUserOrder(ctx, "u2", order.OrderId); !errors.Is(err, ErrNotOrderOwner) {
+		t.Errorf("CancelUserOrder() by another user error = %v, want ErrNotOrderOwner", err)
+	}
+	if _, err := s.GetUserOrder(ctx, "u2", order.OrderId); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("GetUserOrder() by another user error = %v, want ErrOrderNotFound", err)
+	}
+
+	cancelled, err := s.CancelUserOrder(ctx, "u1", order.OrderId)
+	if err != nil {
+		t.Fatalf("CancelUserOrder() by the owner error = %v", err)
+	}
+	if cancelled.OrderStatus != STATUS_CANCELLED {
+		t.Errorf("status = %s, want cancelled", cancelled.OrderStatus)
+	}
+	if got, err := s.GetUserOrder(ctx, "u1", order.OrderId); err != nil || got.OrderId != order.OrderId {
+		t.Errorf("GetUserOrder() by the owner = %v, %v", got, err)
+	}
+}
@@ -1,11 +1,13 @@
-package order
-
-import (
-	"math/rand"
-)
-
-func SimulatePrice(basePrice float64) float64 {
-	// Random value between -1.00 and +1.00
-	change := (rand.Float64() * 2) - 1
-	return basePrice + change
+package order
+
+import (
+	"math/rand"
+
+	"github.com/shopspring/decimal"
+)
+
+func SimulatePrice(basePrice decimal.Decimal) decimal.Decimal {
+	// Random value between -1.00 and +1.00
+	change := decimal.NewFromFloat((rand.Float64() * 2) - 1)
+	return basePrice.Add(change)
 }
\ No newline at end of file
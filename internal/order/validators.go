@@ -1,15 +1,15 @@
-package order
-
-import (
-	"fmt"
-
-	"github.com/google/uuid"
-)
-
-func IsValidUUID(u string) bool {
-	_, err := uuid.Parse(u)
-	if err!=nil{
-		fmt.Print(err.Error())
-	}
-	return err == nil
+package order
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func IsValidUUID(u string) bool {
+	_, err := uuid.Parse(u)
+	if err!=nil{
+		fmt.Print(err.Error())
+	}
+	return err == nil
 }
\ No newline at end of file
@@ -0,0 +1,135 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds say what went wrong independent of the transport, status.go maps them to gRPC
+// and HTTP codes.
+const (
+	KIND_INVALID_ARGUMENT    = "invalid_argument"
+	KIND_NOT_FOUND           = "not_found"
+	KIND_PERMISSION_DENIED   = "permission_denied"
+	KIND_FAILED_PRECONDITION = "failed_precondition"
+	KIND_REJECTED            = "rejected"
+	KIND_UNAVAILABLE         = "unavailable"
+	KIND_INTERNAL            = "internal"
+)
+
+const REASON_INTERNAL = "INTERNAL"
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Error is a domain error. Two errors with the same kind and reason match with errors.Is,
+// so package level sentinels still match after Withf or Wrap gave them a new message.
+type Error struct {
+	Kind     string
+	Reason   string
+	Message  string
+	Fields   []FieldViolation
+	Metadata map[string]string
+	cause    error
+}
+
+func New(kind string, reason string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// InvalidField is a bad request caused by a single field.
+func InvalidField(reason string, field string, message string) *Error {
+	return New(KIND_INVALID_ARGUMENT, reason, message).WithField(field, message)
+}
+
+func NotFound(reason string, message string) *Error {
+	return New(KIND_NOT_FOUND, reason, message)
+}
+
+func PermissionDenied(reason string, message string) *Error {
+	return New(KIND_PERMISSION_DENIED, reason, message)
+}
+
+func FailedPrecondition(reason string, message string) *Error {
+	return New(KIND_FAILED_PRECONDITION, reason, message)
+}
+
+// Rejected is a valid request refused by a business rule, a halt or a risk check.
+func Rejected(reason string, message string) *Error {
+	return New(KIND_REJECTED, reason, message)
+}
+
+func Unavailable(reason string, message string) *Error {
+	return New(KIND_UNAVAILABLE, reason, message)
+}
+
+func Internal(cause error) *Error {
+	return New(KIND_INTERNAL, REASON_INTERNAL, cause.Error()).Wrap(cause)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Reason == e.Reason
+}
+
+// Withf returns a copy with a new message.
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	c := e.clone()
+	c.Message = fmt.Sprintf(format, args...)
+	for i := range c.Fields {
+		c.Fields[i].Description = c.Message
+	}
+	return c
+}
+
+// Wrap returns a copy that keeps cause for errors.Is/As and logging.
+func (e *Error) Wrap(cause error) *Error {
+	c := e.clone()
+	c.cause = cause
+	return c
+}
+
+func (e *Error) WithField(field string, description string) *Error {
+	c := e.clone()
+	c.Fields = append(c.Fields, FieldViolation{Field: field, Description: description})
+	return c
+}
+
+func (e *Error) WithMetadata(key string, value string) *Error {
+	c := e.clone()
+	c.Metadata[key] = value
+	return c
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	c.Fields = append([]FieldViolation(nil), e.Fields...)
+	c.Metadata = make(map[string]string, len(e.Metadata))
+	for k, v := range e.Metadata {
+		c.Metadata[k] = v
+	}
+	return &c
+}
+
+// FromError returns err as a domain error, anything unknown becomes an internal error.
+func FromError(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
@@ -0,0 +1,99 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const responseField = "response"
+
+// ErrorInterceptor is the one place handler errors are turned into what goes on the wire.
+// In legacy mode a failed call answers with an empty response whose common.Response
+// carries the HTTP style code, like every handler used to do itself. Otherwise the call
+// fails with a gRPC status and error details.
+type ErrorInterceptor struct {
+	legacy bool
+}
+
+func NewErrorInterceptor(legacy bool) *ErrorInterceptor {
+	return &ErrorInterceptor{
+		legacy: legacy,
+	}
+}
+
+func (i *ErrorInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		res, err := handler(ctx, req)
+		if err == nil {
+			return res, nil
+		}
+		if i.legacy && isDomainError(err) {
+			if legacy, ok := legacyResponse(info.FullMethod, err); ok {
+				return legacy, nil
+			}
+		}
+		return nil, Status(err).Err()
+	}
+}
+
+func (i *ErrorInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return Status(err).Err()
+		}
+		return nil
+	}
+}
+
+// isDomainError leaves errors that already are a gRPC status, like the ones from the auth
+// interceptor, as errors in legacy mode too.
+func isDomainError(err error) bool {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return true
+	}
+	_, isStatus := status.FromError(err)
+	return !isStatus
+}
+
+// legacyResponse builds the method's response message from the registry and fills in its
+// common.Response field.
+func legacyResponse(fullMethod string, err error) (interface{}, bool) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return nil, false
+	}
+	desc, findErr := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if findErr != nil {
+		return nil, false
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, false
+	}
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, false
+	}
+	messageType, findErr := protoregistry.GlobalTypes.FindMessageByName(methodDesc.Output().FullName())
+	if findErr != nil {
+		return nil, false
+	}
+	res := messageType.New()
+	field := res.Descriptor().Fields().ByName(responseField)
+	if field == nil || field.Message() == nil {
+		return nil, false
+	}
+	body := Response(err)
+	if field.Message().FullName() != body.ProtoReflect().Descriptor().FullName() {
+		return nil, false
+	}
+	res.Set(field, protoreflect.ValueOfMessage(body.ProtoReflect()))
+	return res.Interface(), true
+}
@@ -0,0 +1,101 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	common "github.com/tanmaygupta069/order-service-go/generated/common"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ERROR_DOMAIN is sent as the domain of every google.rpc.ErrorInfo.
+const ERROR_DOMAIN = "order-service"
+
+var grpcCodes = map[string]codes.Code{
+	KIND_INVALID_ARGUMENT:    codes.InvalidArgument,
+	KIND_NOT_FOUND:           codes.NotFound,
+	KIND_PERMISSION_DENIED:   codes.PermissionDenied,
+	KIND_FAILED_PRECONDITION: codes.FailedPrecondition,
+	KIND_REJECTED:            codes.FailedPrecondition,
+	KIND_UNAVAILABLE:         codes.Unavailable,
+	KIND_INTERNAL:            codes.Internal,
+}
+
+// httpCodes are the codes common.Response has always carried for these failures, an
+// order that isn't yours stays a 401 so older clients see no change.
+var httpCodes = map[string]int{
+	KIND_INVALID_ARGUMENT:    http.StatusBadRequest,
+	KIND_NOT_FOUND:           http.StatusNotFound,
+	KIND_PERMISSION_DENIED:   http.StatusUnauthorized,
+	KIND_FAILED_PRECONDITION: http.StatusBadRequest,
+	KIND_REJECTED:            http.StatusUnprocessableEntity,
+	KIND_UNAVAILABLE:         http.StatusServiceUnavailable,
+	KIND_INTERNAL:            http.StatusInternalServerError,
+}
+
+// Status converts err to a gRPC status with an ErrorInfo detail, plus a BadRequest detail
+// listing field violations when there are any. Errors that already are a gRPC status are
+// returned unchanged.
+func Status(err error) *status.Status {
+	var appErr *Error
+	if !errors.As(err, &appErr) {
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+		appErr = Internal(err)
+	}
+
+	message := appErr.Message
+	if appErr.Kind == KIND_INTERNAL {
+		// don't leak driver and provider errors to clients
+		fmt.Printf("internal error : %v\n", err)
+		message = "internal error"
+	}
+	code, ok := grpcCodes[appErr.Kind]
+	if !ok {
+		code = codes.Unknown
+	}
+	st := status.New(code, message)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason:   appErr.Reason,
+			Domain:   ERROR_DOMAIN,
+			Metadata: appErr.Metadata,
+		},
+	}
+	if len(appErr.Fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range appErr.Fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Description,
+			})
+		}
+		details = append(details, badRequest)
+	}
+	withDetails, detailErr := st.WithDetails(details...)
+	if detailErr != nil {
+		fmt.Printf("error attaching error details : %v\n", detailErr)
+		return st
+	}
+	return withDetails
+}
+
+// Response converts err to the legacy common.Response body.
+func Response(err error) *common.Response {
+	appErr := FromError(err)
+	code, ok := httpCodes[appErr.Kind]
+	if !ok {
+		code = http.StatusInternalServerError
+	}
+	return &common.Response{
+		Code:    int32(code),
+		Message: appErr.Message,
+		Reason:  appErr.Reason,
+	}
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// AuditServiceImp keeps the audit trail of privileged calls in the audit_logs table.
+type AuditServiceImp struct {
+	mysql *mysql.SqlServiceImplementation[mysql.AuditLogs]
+}
+
+func NewAuditService() auth.Auditor {
+	return &AuditServiceImp{
+		mysql: mysql.NewSqlClient[mysql.AuditLogs](),
+	}
+}
+
+// Record is detached from the call's cancellation, a client hanging up right after a
+// privileged call must not drop its audit entry.
+func (r *AuditServiceImp) Record(ctx context.Context, entry *auth.AuditEntry) {
+	log := &mysql.AuditLogs{
+		Actor:   entry.Actor,
+		Roles:   strings.Join(entry.Roles, ","),
+		Method:  entry.Method,
+		Outcome: entry.Outcome,
+		Error:   entry.Error,
+	}
+	if message, ok := entry.Request.(proto.Message); ok {
+		if data, err := protojson.Marshal(message); err == nil {
+			log.Request = string(data)
+		}
+	}
+	if err := r.mysql.Insert(context.WithoutCancel(ctx), log); err != nil {
+		// the call already happened, so keep the entry in the service log at least
+		fmt.Printf("error writing audit log for %s by %s (%s) : %v\n", entry.Method, entry.Actor, entry.Outcome, err)
+	}
+}
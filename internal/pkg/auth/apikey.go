@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"gorm.io/gorm"
+)
+
+// API keys look like osk_<prefix>_<secret>. The prefix is stored in clear for lookup,
+// only a sha256 of the secret is kept.
+const apiKeyTag = "osk"
+
+// SCOPE_ACT_AS_USER lets a key name the user it acts for in the x-on-behalf-of header.
+const SCOPE_ACT_AS_USER = "act_as_user"
+
+// lastUsedResolution limits how often a busy key rewrites its last used timestamp.
+const lastUsedResolution = time.Minute
+
+var ErrInvalidApiKey = errors.New("invalid api key")
+
+type ApiKeyStore interface {
+	CreateKey(ctx context.Context, name string, createdBy string, scopes []string, expiresAt *time.Time) (string, *mysql.ApiKeys, error)
+	ListKeys(ctx context.Context) ([]*mysql.ApiKeys, error)
+	RevokeKey(ctx context.Context, id string) (*mysql.ApiKeys, error)
+	Authenticate(ctx context.Context, key string, onBehalfOf string) (*Principal, error)
+}
+
+type ApiKeyStoreImp struct {
+	mysql *mysql.SqlServiceImplementation[mysql.ApiKeys]
+}
+
+func NewApiKeyStore() ApiKeyStore {
+	return &ApiKeyStoreImp{
+		mysql: mysql.NewSqlClient[mysql.ApiKeys](),
+	}
+}
+
+// CreateKey returns the plain key, it can't be recovered after this call.
+func (r *ApiKeyStoreImp) CreateKey(ctx context.Context, name string, createdBy string, scopes []string, expiresAt *time.Time) (string, *mysql.ApiKeys, error) {
+	prefix, err := randomString(6, hex.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomString(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", nil, err
+	}
+	record := &mysql.ApiKeys{
+		Id:        uuid.New().String(),
+		Prefix:    prefix,
+		Hash:      hashSecret(secret),
+		Name:      name,
+		CreatedBy: createdBy,
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err = r.mysql.Insert(ctx, record); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s_%s_%s", apiKeyTag, prefix, secret), record, nil
+}
+
+func (r *ApiKeyStoreImp) ListKeys(ctx context.Context) ([]*mysql.ApiKeys, error) {
+	keys, err := r.mysql.GetAll(ctx, mysql.Where().OrderBy("created_at", false))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mysql.ApiKeys, len(keys))
+	for i := range keys {
+		result[i] = &keys[i]
+	}
+	return result, nil
+}
+
+func (r *ApiKeyStoreImp) RevokeKey(ctx context.Context, id string) (*mysql.ApiKeys, error) {
+	record, err := r.mysql.GetOne(ctx, mysql.Where(mysql.Eq("id", id)))
+	if err != nil {
+		return nil, err
+	}
+	if record.RevokedAt == nil {
+		now := time.Now()
+		record.RevokedAt = &now
+		if err = r.mysql.Update(ctx, record); err != nil {
+			return nil, err
+		}
+	}
+	return record, nil
+}
+
+// Authenticate checks the key and returns a principal for it. Scopes that name a role
+// grant that role, and onBehalfOf is only honoured with SCOPE_ACT_AS_USER, in which case
+// the principal is that user with no roles.
+func (r *ApiKeyStoreImp) Authenticate(ctx context.Context, key string, onBehalfOf string) (*Principal, error) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag {
+		return nil, ErrInvalidApiKey
+	}
+	record, err := r.mysql.GetOne(ctx, mysql.Where(mysql.Eq("prefix", parts[1])))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidApiKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(record.Hash), []byte(hashSecret(parts[2]))) != 1 {
+		return nil, ErrInvalidApiKey
+	}
+	now := time.Now()
+	if record.RevokedAt != nil {
+		return nil, fmt.Errorf("api key has been revoked")
+	}
+	if record.ExpiresAt != nil && now.After(*record.ExpiresAt) {
+		return nil, fmt.Errorf("api key has expired")
+	}
+
+	principal := &Principal{
+		// names aren't unique, the id keeps audit entries and rate limits apart
+		UserId: "apikey:" + record.Id,
+		Roles:  make([]string, 0),
+		Scopes: make([]string, 0),
+	}
+	for _, scope := range strings.Split(record.Scopes, ",") {
+		if scope == "" {
+			continue
+		}
+		principal.Scopes = append(principal.Scopes, scope)
+		if scope == ROLE_ADMIN || scope == ROLE_OPERATOR {
+			principal.Roles = append(principal.Roles, scope)
+		}
+	}
+	if onBehalfOf != "" {
+		if !principal.HasScope(SCOPE_ACT_AS_USER) {
+			return nil, fmt.Errorf("api key is not allowed to act on behalf of users")
+		}
+		// acting as a user the key has that user's rights only, not its own roles
+		principal.UserId = onBehalfOf
+		principal.Roles = make([]string, 0)
+	}
+
+	if record.LastUsedAt == nil || now.Sub(*record.LastUsedAt) > lastUsedResolution {
+		record.LastUsedAt = &now
+		if err = r.mysql.Update(ctx, record); err != nil {
+			fmt.Printf("error updating last used time of api key %s : %v\n", record.Prefix, err)
+		}
+	}
+	return principal, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomString(size int, encode func([]byte) string) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return encode(buf), nil
+}
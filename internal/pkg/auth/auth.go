@@ -1,58 +1,203 @@
-package auth
-
-import (
-	"fmt"
-
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/tanmaygupta069/order-service-go/config"
-	"google.golang.org/grpc/metadata"
-)
-
-type AuthPackage interface {
-	GetTokenFromMetadata(md metadata.MD) (string, error)
-	ExtractUserIDFromToken(tokenString string) (string, error)
-}
-
-var cfg, _ = config.GetConfig()
-
-type AuthPackageImp struct{
-}
-
-
-func NewAuthPackage()AuthPackage{
-	return &AuthPackageImp{
-	}
-}
-
-func (r *AuthPackageImp) GetTokenFromMetadata(md metadata.MD) (string, error) {
-	token := md.Get("Authorization")
-	if len(token) == 0 {
-		return "", fmt.Errorf("no token found")
-	}
-	return token[0], nil
-}
-
-func (r *AuthPackageImp) ExtractUserIDFromToken(tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Make sure the token signing method is as expected
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(cfg.JwtSecret), nil
-	})
-
-	if err != nil {
-		return "", fmt.Errorf("error parsing token: %v", err)
-	}
-
-	// Extract claims
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		email, ok := claims["email"].(string)
-		if !ok {
-			return "", fmt.Errorf("email not found in token")
-		}
-		return email, nil
-	}
-
-	return "", fmt.Errorf("invalid token")
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+type AuthPackage interface {
+	AuthenticateMetadata(ctx context.Context, md metadata.MD) (*Principal, error)
+	AuthenticateClientCert(authInfo credentials.AuthInfo) (*Principal, error)
+	GetTokenFromMetadata(md metadata.MD) (string, error)
+	ExtractUserIDFromToken(ctx context.Context, tokenString string) (string, error)
+	ExtractPrincipalFromToken(ctx context.Context, tokenString string) (*Principal, error)
+	ExtractClaimsFromToken(ctx context.Context, tokenString string) (jwt.MapClaims, error)
+}
+
+var cfg, _ = config.GetConfig()
+
+type AuthPackageImp struct{
+	keySet *JwksKeySet
+	parser *jwt.Parser
+	revocations RevocationStore
+	apiKeys ApiKeyStore
+	clientCerts ClientCertIdentities
+	// maxLifetime is how long revocations are kept, no accepted token may live longer
+	maxLifetime time.Duration
+}
+
+// asymmetricMethods are accepted when a JWKS is configured, HS256 only when JWT_SECRET is set.
+var asymmetricMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+func NewAuthPackage()AuthPackage{
+	jwtConfig := cfg.JwtConfig
+	methods := make([]string, 0)
+	var keySet *JwksKeySet
+	if jwtConfig.JwksUrl != "" || jwtConfig.JwksFile != "" {
+		keySet = NewJwksKeySet(jwtConfig.JwksUrl, jwtConfig.JwksFile, time.Duration(jwtConfig.JwksRefreshMinutes)*time.Minute)
+		methods = append(methods, asymmetricMethods...)
+	}
+	if cfg.JwtSecret != "" {
+		methods = append(methods, "HS256")
+	}
+	clientCerts, err := ParseClientCertIdentities(cfg.TlsConfig.ClientIdentities)
+	if err != nil {
+		fmt.Printf("error parsing client certificate identities, ignoring them : %v\n", err)
+		clientCerts = make(ClientCertIdentities)
+	}
+	return &AuthPackageImp{
+		keySet: keySet,
+		parser: newTokenParser(jwtConfig, methods),
+		revocations: NewRevocationStore(),
+		apiKeys: NewApiKeyStore(),
+		clientCerts: clientCerts,
+		maxLifetime: time.Duration(jwtConfig.MaxLifetimeMinutes) * time.Minute,
+	}
+}
+
+// newTokenParser validates the claims jwtConfig asks for on tokens signed with methods.
+func newTokenParser(jwtConfig config.JwtConfig, methods []string) *jwt.Parser {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(time.Duration(jwtConfig.ClockSkewSeconds) * time.Second),
+	}
+	if jwtConfig.Issuer != "" {
+		options = append(options, jwt.WithIssuer(jwtConfig.Issuer))
+	}
+	if jwtConfig.Audience != "" {
+		options = append(options, jwt.WithAudience(jwtConfig.Audience))
+	}
+	if jwtConfig.RequireExpiry {
+		options = append(options, jwt.WithExpirationRequired())
+	}
+	if jwtConfig.MaxLifetimeMinutes > 0 {
+		// an iat in the future would stretch the lifetime and dodge user revocations
+		options = append(options, jwt.WithIssuedAt())
+	}
+	return jwt.NewParser(options...)
+}
+
+// AuthenticateMetadata accepts either an x-api-key header, optionally with x-on-behalf-of,
+// or a bearer token in Authorization.
+func (r *AuthPackageImp) AuthenticateMetadata(ctx context.Context, md metadata.MD) (*Principal, error) {
+	if key := md.Get("x-api-key"); len(key) > 0 {
+		onBehalfOf := ""
+		if user := md.Get("x-on-behalf-of"); len(user) > 0 {
+			onBehalfOf = user[0]
+		}
+		return r.apiKeys.Authenticate(ctx, key[0], onBehalfOf)
+	}
+	token, err := r.GetTokenFromMetadata(md)
+	if err != nil {
+		return nil, err
+	}
+	return r.ExtractPrincipalFromToken(ctx, token)
+}
+
+// AuthenticateClientCert maps a verified mTLS client certificate to a service principal.
+func (r *AuthPackageImp) AuthenticateClientCert(authInfo credentials.AuthInfo) (*Principal, error) {
+	return r.clientCerts.Principal(authInfo)
+}
+
+func (r *AuthPackageImp) GetTokenFromMetadata(md metadata.MD) (string, error) {
+	token := md.Get("Authorization")
+	if len(token) == 0 {
+		return "", fmt.Errorf("no token found")
+	}
+	return strings.TrimPrefix(token[0], "Bearer "), nil
+}
+
+func (r *AuthPackageImp) ExtractUserIDFromToken(ctx context.Context, tokenString string) (string, error) {
+	claims, err := r.ExtractClaimsFromToken(ctx, tokenString)
+	if err != nil {
+		return "", err
+	}
+	email, ok := claims["email"].(string)
+	if !ok {
+		return "", fmt.Errorf("email not found in token")
+	}
+	return email, nil
+}
+
+// ExtractPrincipalFromToken validates the token and returns the caller it identifies.
+func (r *AuthPackageImp) ExtractPrincipalFromToken(ctx context.Context, tokenString string) (*Principal, error) {
+	claims, err := r.ExtractClaimsFromToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	principal := principalFromClaims(claims)
+	if principal.UserId == "" {
+		return nil, fmt.Errorf("email not found in token")
+	}
+	return principal, nil
+}
+
+func (r *AuthPackageImp) ExtractClaimsFromToken(ctx context.Context, tokenString string) (jwt.MapClaims, error) {
+	token, err := r.parser.Parse(tokenString, r.keyFunc)
+
+	if err != nil {
+		return nil, fmt.Errorf("error parsing token: %v", err)
+	}
+
+	// Extract claims
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		if err := r.checkLifetime(claims); err != nil {
+			return nil, err
+		}
+		revoked, err := r.revocations.IsRevoked(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, fmt.Errorf("token has been revoked")
+		}
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// checkLifetime refuses tokens that could outlive their revocation. Revocations are kept
+// for maxLifetime, so a token has to carry exp and iat no further apart than that.
+func (r *AuthPackageImp) checkLifetime(claims jwt.MapClaims) error {
+	if r.maxLifetime <= 0 {
+		return nil
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return fmt.Errorf("token has no expiry")
+	}
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		return fmt.Errorf("token has no issue time")
+	}
+	if lifetime := expiresAt.Sub(issuedAt.Time); lifetime > r.maxLifetime {
+		return fmt.Errorf("token lifetime %s is above the limit of %s", lifetime, r.maxLifetime)
+	}
+	return nil
+}
+
+// keyFunc picks the verification key from the token's alg and kid headers.
+func (r *AuthPackageImp) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		if r.keySet == nil {
+			return nil, fmt.Errorf("no jwks configured for %v tokens", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return r.keySet.Key(kid)
+	case *jwt.SigningMethodHMAC:
+		if cfg.JwtSecret == "" {
+			return nil, fmt.Errorf("hmac tokens are not accepted, JWT_SECRET is not set")
+		}
+		return []byte(cfg.JwtSecret), nil
+	}
+	return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 }
\ No newline at end of file
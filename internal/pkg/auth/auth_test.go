@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tanmaygupta069/order-service-go/config"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+)
+
+const testSecret = "test-secret"
+
+// newTestAuthPackage accepts HS256 tokens living up to a day, without requiring exp the
+// way JWT_REQUIRE_EXP=false does.
+func newTestAuthPackage(t *testing.T) (*AuthPackageImp, RevocationStore) {
+	t.Helper()
+	previous := cfg
+	cfg = &config.Config{JwtSecret: testSecret}
+	t.Cleanup(func() { cfg = previous })
+
+	jwtConfig := config.JwtConfig{RequireExpiry: false, MaxLifetimeMinutes: 24 * 60}
+	revocations := &RevocationStoreImp{
+		redis:       Redis.NewMemoryRedis(),
+		maxLifetime: time.Duration(jwtConfig.MaxLifetimeMinutes) * time.Minute,
+	}
+	return &AuthPackageImp{
+		parser:      newTokenParser(jwtConfig, []string{"HS256"}),
+		revocations: revocations,
+		maxLifetime: time.Duration(jwtConfig.MaxLifetimeMinutes) * time.Minute,
+	}, revocations
+}
+
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("error signing token : %v", err)
+	}
+	return token
+}
+
+func TestExtractClaimsBoundsTokenLifetime(t *testing.T) {
+	auth, _ := newTestAuthPackage(t)
+	now := time.Now()
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		valid  bool
+	}{
+		{"an hour", jwt.MapClaims{"email": "u1", "iat": now.Unix(), "exp": now.Add(time.Hour).Unix()}, true},
+		{"exactly the limit", jwt.MapClaims{"email": "u1", "iat": now.Add(-time.Hour).Unix(), "exp": now.Add(23 * time.Hour).Unix()}, true},
+		{"thirty days", jwt.MapClaims{"email": "u1", "iat": now.Unix(), "exp": now.Add(30 * 24 * time.Hour).Unix()}, false},
+		{"no expiry", jwt.MapClaims{"email": "u1", "iat": now.Unix()}, false},
+		{"no issue time", jwt.MapClaims{"email": "u1", "exp": now.Add(time.Hour).Unix()}, false},
+		{"issued in the future", jwt.MapClaims{"email": "u1", "iat": now.Add(29 * 24 * time.Hour).Unix(), "exp": now.Add(30 * 24 * time.Hour).Unix()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.ExtractClaimsFromToken(context.Background(), signTestToken(t, tt.claims))
+			if tt.valid && err != nil {
+				t.Errorf("ExtractClaimsFromToken() error = %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Errorf("ExtractClaimsFromToken() accepted the token")
+			}
+		})
+	}
+}
+
+func TestLongLivedTokenCantOutliveUserRevocation(t *testing.T) {
+	auth, revocations := newTestAuthPackage(t)
+	issuedAt := time.Now().Add(-time.Minute)
+	longLived := signTestToken(t, jwt.MapClaims{"email": "u1", "iat": issuedAt.Unix(), "exp": issuedAt.Add(30 * 24 * time.Hour).Unix()})
+	dayLong := signTestToken(t, jwt.MapClaims{"email": "u1", "iat": issuedAt.Unix(), "exp": issuedAt.Add(24 * time.Hour).Unix()})
+
+	// a token that would still be valid once the revocation entry expires is never accepted
+	if _, err := auth.ExtractClaimsFromToken(context.Background(), longLived); err == nil {
+		t.Fatalf("30 day token was accepted, it would outlive a revocation kept for a day")
+	}
+	if _, err := auth.ExtractClaimsFromToken(context.Background(), dayLong); err != nil {
+		t.Fatalf("ExtractClaimsFromToken() error = %v", err)
+	}
+	if err := revocations.RevokeUserTokens(context.Background(), "u1", time.Now()); err != nil {
+		t.Fatalf("RevokeUserTokens() error = %v", err)
+	}
+	if _, err := auth.ExtractClaimsFromToken(context.Background(), dayLong); err == nil {
+		t.Errorf("revoked token was accepted")
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// ClientCertIdentities maps the identity of a verified client certificate to the roles
+// its service gets. Identities are matched against URI SANs, then DNS SANs, then the
+// subject common name.
+type ClientCertIdentities map[string][]string
+
+// ParseClientCertIdentities reads entries of the form identity=role|role. The identity is
+// split at the last "=" so SPIFFE ids and other URIs with query strings still work.
+func ParseClientCertIdentities(entries []string) (ClientCertIdentities, error) {
+	identities := make(ClientCertIdentities)
+	for _, entry := range entries {
+		idx := strings.LastIndex(entry, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid client identity %q, expected identity=role|role", entry)
+		}
+		roles := make([]string, 0)
+		for _, role := range strings.Split(entry[idx+1:], "|") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+		identities[strings.TrimSpace(entry[:idx])] = roles
+	}
+	return identities, nil
+}
+
+// Principal returns the service principal for the verified certificate in authInfo.
+func (c ClientCertIdentities) Principal(authInfo credentials.AuthInfo) (*Principal, error) {
+	tlsInfo, ok := authInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, fmt.Errorf("connection is not using tls")
+	}
+	if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("no verified client certificate")
+	}
+	cert := tlsInfo.State.PeerCertificates[0]
+	for _, identity := range certIdentities(cert) {
+		if roles, ok := c[identity]; ok {
+			return &Principal{
+				UserId: "service:" + identity,
+				Roles:  roles,
+				Scopes: make([]string, 0),
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("client certificate %q is not mapped to a principal", cert.Subject.CommonName)
+}
+
+func certIdentities(cert *x509.Certificate) []string {
+	identities := make([]string, 0)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	identities = append(identities, cert.DNSNames...)
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	return identities
+}
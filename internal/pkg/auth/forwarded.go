@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// GATEWAY_HEADER carries the token of the REST gateway of this process on every call it
+// forwards.
+const GATEWAY_HEADER = "x-gateway-token"
+
+// gatewayToken is made up at start so only the gateway running in this process can mark
+// a call as forwarded. Calls from a gateway elsewhere don't know it and count as direct.
+var gatewayToken = newGatewayToken()
+
+func newGatewayToken() string {
+	token, err := randomString(32, hex.EncodeToString)
+	if err != nil {
+		panic(fmt.Sprintf("error generating gateway token : %v", err))
+	}
+	return token
+}
+
+// GatewayMetadata is the metadata the gateway adds to the calls it forwards, it has the
+// signature of grpc-gateway's runtime.WithMetadata.
+func GatewayMetadata(ctx context.Context, req *http.Request) metadata.MD {
+	return metadata.Pairs(GATEWAY_HEADER, gatewayToken)
+}
+
+// IsGatewayForwarded reports whether the call came through the gateway of this process.
+// The peer of such a call is the gateway, not the REST client, so neither its address
+// nor its client certificate says anything about the caller.
+func IsGatewayForwarded(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	// a REST client can add values of its own through Grpc-Metadata headers
+	for _, value := range md.Get(GATEWAY_HEADER) {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(gatewayToken)) == 1 {
+			return true
+		}
+	}
+	return false
+}
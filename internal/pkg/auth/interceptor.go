@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	common "github.com/tanmaygupta069/order-service-go/generated/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	POLICY_PUBLIC   = "public"
+	POLICY_USER     = "user"
+	POLICY_OPERATOR = "operator"
+	POLICY_ADMIN    = "admin"
+)
+
+const (
+	AUDIT_OUTCOME_SUCCESS = "success"
+	AUDIT_OUTCOME_DENIED  = "denied"
+	AUDIT_OUTCOME_ERROR   = "error"
+)
+
+// policyRoles is the role a caller needs for each privileged policy.
+var policyRoles = map[string]string{
+	POLICY_OPERATOR: ROLE_OPERATOR,
+	POLICY_ADMIN:    ROLE_ADMIN,
+}
+
+// AuditEntry describes one privileged call, whether it was allowed or not.
+type AuditEntry struct {
+	Actor   string
+	Roles   []string
+	Method  string
+	Request interface{}
+	Outcome string
+	Error   string
+}
+
+type Auditor interface {
+	Record(ctx context.Context, entry *AuditEntry)
+}
+
+// AuthInterceptor authenticates every call once and checks it against a table of
+// full method name to policy. Methods missing from the table are refused.
+type AuthInterceptor struct {
+	auth     AuthPackage
+	auditor  Auditor
+	policies map[string]string
+}
+
+func NewAuthInterceptor(auth AuthPackage, auditor Auditor, policies ...map[string]string) *AuthInterceptor {
+	merged := make(map[string]string)
+	for _, table := range policies {
+		for method, policy := range table {
+			merged[method] = policy
+		}
+	}
+	return &AuthInterceptor{
+		auth:     auth,
+		auditor:  auditor,
+		policies: merged,
+	}
+}
+
+func (i *AuthInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		authCtx, principal, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			i.audit(ctx, info.FullMethod, principal, req, err, AUDIT_OUTCOME_DENIED)
+			return nil, err
+		}
+		res, err := handler(authCtx, req)
+		i.audit(ctx, info.FullMethod, principal, req, responseError(res, err), AUDIT_OUTCOME_SUCCESS)
+		return res, err
+	}
+}
+
+func (i *AuthInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		authCtx, principal, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			i.audit(ctx, info.FullMethod, principal, nil, err, AUDIT_OUTCOME_DENIED)
+			return err
+		}
+		err = handler(srv, &principalStream{ServerStream: ss, ctx: authCtx})
+		i.audit(ctx, info.FullMethod, principal, nil, err, AUDIT_OUTCOME_SUCCESS)
+		return err
+	}
+}
+
+// authorize returns the principal even when it is refused so denials can be audited.
+func (i *AuthInterceptor) authorize(ctx context.Context, method string) (context.Context, *Principal, error) {
+	policy, ok := i.policies[method]
+	if !ok {
+		return nil, nil, status.Errorf(codes.PermissionDenied, "no access policy for %s", method)
+	}
+
+	principal, err := i.authenticate(ctx)
+	if policy == POLICY_PUBLIC {
+		// a public call may still carry a token, attach it when it is valid
+		if err == nil {
+			ctx = ContextWithPrincipal(ctx, principal)
+		}
+		return ctx, principal, nil
+	}
+	if err != nil {
+		return nil, nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if role, privileged := policyRoles[policy]; privileged && !principal.HasRole(role) {
+		return nil, principal, status.Errorf(codes.PermissionDenied, "%s requires the %s role", method, role)
+	}
+	return ContextWithPrincipal(ctx, principal), principal, nil
+}
+
+// authenticate prefers credentials in metadata, a client certificate only identifies the
+// caller when no token or api key was sent, so services can still forward user tokens.
+// Calls forwarded by the gateway arrive on the gateway's certificate, which is never
+// taken as the identity of the REST client.
+func (i *AuthInterceptor) authenticate(ctx context.Context) (*Principal, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if len(md.Get("x-api-key")) == 0 && len(md.Get("authorization")) == 0 && !IsGatewayForwarded(ctx) {
+		if p, found := peer.FromContext(ctx); found && p.AuthInfo != nil {
+			principal, err := i.auth.AuthenticateClientCert(p.AuthInfo)
+			if err == nil {
+				return principal, nil
+			}
+			if !ok {
+				return nil, err
+			}
+		}
+	}
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
+	return i.auth.AuthenticateMetadata(ctx, md)
+}
+
+// audit records calls to privileged methods, everything else is skipped.
+func (i *AuthInterceptor) audit(ctx context.Context, method string, principal *Principal, req interface{}, err error, outcome string) {
+	if i.auditor == nil {
+		return
+	}
+	if _, privileged := policyRoles[i.policies[method]]; !privileged {
+		return
+	}
+	entry := &AuditEntry{
+		Method:  method,
+		Request: req,
+		Outcome: outcome,
+	}
+	if principal != nil {
+		entry.Actor = principal.UserId
+		entry.Roles = principal.Roles
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		if outcome == AUDIT_OUTCOME_SUCCESS {
+			entry.Outcome = AUDIT_OUTCOME_ERROR
+		}
+	}
+	i.auditor.Record(ctx, entry)
+}
+
+// responseError also treats a failure reported in common.Response as an error, since
+// most handlers answer with a status code in the body and a nil error.
+func responseError(res interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	if withResponse, ok := res.(interface{ GetResponse() *common.Response }); ok {
+		if response := withResponse.GetResponse(); response != nil && response.Code >= http.StatusBadRequest {
+			return fmt.Errorf("%d %s", response.Code, response.Message)
+		}
+	}
+	return nil
+}
+
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context {
+	return s.ctx
+}
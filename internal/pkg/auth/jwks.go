@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// minUnknownKidRefresh stops tokens with made up kids from making us refetch the JWKS on every call.
+const minUnknownKidRefresh = time.Minute
+
+// failedReloadBackoff is how long a failed reload is not retried, so an unreachable source
+// costs one fetch a minute rather than one per call.
+const failedReloadBackoff = time.Minute
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// JwksKeySet caches the public keys of a JWKS document read from a file or URL and
+// reloads them once they are older than the refresh interval.
+type JwksKeySet struct {
+	url       string
+	file      string
+	refresh   time.Duration
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+	failedAt  time.Time
+}
+
+func NewJwksKeySet(url string, file string, refresh time.Duration) *JwksKeySet {
+	return &JwksKeySet{
+		url:     url,
+		file:    file,
+		refresh: refresh,
+		keys:    make(map[string]interface{}),
+	}
+}
+
+// Key returns the public key for kid. An unknown kid forces a reload so a freshly
+// rotated key is picked up without waiting for the refresh interval.
+func (k *JwksKeySet) Key(kid string) (interface{}, error) {
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	age := time.Since(k.fetchedAt)
+	backingOff := time.Since(k.failedAt) < failedReloadBackoff
+	k.mu.RUnlock()
+
+	if ok && (age < k.refresh || backingOff) {
+		return key, nil
+	}
+	if !backingOff && (ok || age >= minUnknownKidRefresh) {
+		if err := k.load(); err != nil {
+			k.mu.Lock()
+			k.failedAt = time.Now()
+			k.mu.Unlock()
+			if ok {
+				// serve the stale key rather than failing every call while the source is down
+				log.Printf("error refreshing jwks, using cached keys : %v", err)
+				return key, nil
+			}
+			return nil, err
+		}
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if key, ok = k.keys[kid]; !ok {
+		return nil, fmt.Errorf("no key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+func (k *JwksKeySet) load() error {
+	data, err := k.read()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJwks(data)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *JwksKeySet) read() ([]byte, error) {
+	if k.file != "" {
+		return os.ReadFile(k.file)
+	}
+	client := http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(k.url)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching jwks : %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d fetching jwks", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func parseJwks(data []byte) (map[string]interface{}, error) {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("error parsing jwks : %v", err)
+	}
+	keys := make(map[string]interface{})
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("skipping jwk %q : %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwks has no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus : %v", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent : %v", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate : %v", err)
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate : %v", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", jwk.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, fmt.Errorf("unsupported key type %s", jwk.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
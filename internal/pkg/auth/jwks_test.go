@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJwksKeySetBacksOffWhileTheSourceIsDown(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key : %v", err)
+	}
+	document, _ := json.Marshal(jsonWebKeySet{Keys: []jsonWebKey{{
+		Kid: "k1",
+		Kty: "RSA",
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(private.E)).Bytes()),
+	}}})
+	var fetches atomic.Int32
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(document)
+	}))
+	defer server.Close()
+
+	keys := NewJwksKeySet(server.URL, "", time.Hour)
+	if _, err := keys.Key("k1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+
+	// the cache goes stale while the source is down
+	down.Store(true)
+	keys.fetchedAt = time.Now().Add(-2 * time.Hour)
+	for i := 0; i < 10; i++ {
+		key, err := keys.Key("k1")
+		if err != nil || key == nil {
+			t.Fatalf("Key() during the outage = %v, %v, want the stale key", key, err)
+		}
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("%d fetches, want the first load and one failed reload", got)
+	}
+	if _, err := keys.Key("unknown"); err == nil {
+		t.Errorf("Key() of an unknown kid during the outage error = nil")
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("unknown kid fetched the jwks during the backoff, %d fetches", got)
+	}
+
+	// once the backoff has passed the source is tried again
+	down.Store(false)
+	keys.failedAt = time.Now().Add(-failedReloadBackoff)
+	if _, err := keys.Key("k1"); err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if got := fetches.Load(); got != 3 {
+		t.Errorf("%d fetches, want a reload after the backoff", got)
+	}
+	if _, err := keys.Key("k1"); err != nil || fetches.Load() != 3 {
+		t.Errorf("Key() after a successful reload = %v with %d fetches, want the cached key", err, fetches.Load())
+	}
+}
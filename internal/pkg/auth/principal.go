@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	ROLE_ADMIN    = "admin"
+	ROLE_OPERATOR = "operator"
+)
+
+// impliedRoles lists the roles each role also carries, admins can do anything an operator can.
+var impliedRoles = map[string][]string{
+	ROLE_ADMIN: {ROLE_OPERATOR},
+}
+
+var ErrMissingPrincipal = status.Error(codes.Unauthenticated, "request is not authenticated")
+
+// Principal is the authenticated caller, put in the request context by the interceptor.
+type Principal struct {
+	UserId string
+	Roles  []string
+	Scopes []string
+	Tier   string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	for _, held := range p.Roles {
+		if held == role || contains(impliedRoles[held], role) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// principalFromClaims reads roles from a "roles" list or a single "role" string, and
+// scopes from a "scopes" list or the space separated OAuth "scope" claim.
+func principalFromClaims(claims jwt.MapClaims) *Principal {
+	principal := &Principal{
+		Roles:  append(stringList(claims["roles"]), stringList(claims["role"])...),
+		Scopes: stringList(claims["scopes"]),
+	}
+	principal.UserId, _ = claims["email"].(string)
+	principal.Tier, _ = claims["tier"].(string)
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = append(principal.Scopes, strings.Fields(scope)...)
+	}
+	return principal
+}
+
+func stringList(claim interface{}) []string {
+	res := make([]string, 0)
+	switch value := claim.(type) {
+	case string:
+		if value != "" {
+			res = append(res, value)
+		}
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
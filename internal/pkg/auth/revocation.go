@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+)
+
+const (
+	revokedTokenPrefix = "revoked:jti:"
+	revokedUserPrefix  = "revoked:user:"
+)
+
+// RevocationStore is the deny list checked after a token's signature and claims are valid.
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userId string, issuedBefore time.Time) error
+	IsRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error)
+}
+
+type RevocationStoreImp struct {
+	redis Redis.RedisInterface
+	// maxLifetime bounds how long an entry has to be kept, no token outlives it
+	maxLifetime time.Duration
+}
+
+func NewRevocationStore() RevocationStore {
+	return &RevocationStoreImp{
+		redis:       Redis.NewRedisClient(),
+		maxLifetime: time.Duration(cfg.JwtConfig.MaxLifetimeMinutes) * time.Minute,
+	}
+}
+
+// RevokeToken denies a single token by jti until it would have expired anyway. A zero
+// expiresAt keeps the entry for the maximum token lifetime, or for good when there is none.
+func (r *RevocationStoreImp) RevokeToken(ctx context.Context, tokenId string, expiresAt time.Time) error {
+	ttl := r.maxLifetime
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt)
+		if ttl <= 0 {
+			return nil
+		}
+	}
+	return r.redis.Set(ctx, revokedTokenPrefix+tokenId, "1", ttlMinutes(ttl))
+}
+
+// RevokeUserTokens denies every token of the user issued before the given time. The parser
+// refuses tokens living longer than maxLifetime, so the entry outlasts every one of them.
+func (r *RevocationStoreImp) RevokeUserTokens(ctx context.Context, userId string, issuedBefore time.Time) error {
+	return r.redis.Set(ctx, revokedUserPrefix+userId, strconv.FormatInt(issuedBefore.Unix(), 10), ttlMinutes(r.maxLifetime))
+}
+
+// IsRevoked fails closed: a redis error is returned so the caller refuses the token.
+func (r *RevocationStoreImp) IsRevoked(ctx context.Context, claims jwt.MapClaims) (bool, error) {
+	if tokenId, ok := claims["jti"].(string); ok && tokenId != "" {
+		revoked, err := r.redis.Exists(ctx, revokedTokenPrefix+tokenId)
+		if err != nil {
+			return false, fmt.Errorf("error checking token revocation : %v", err)
+		}
+		if revoked > 0 {
+			return true, nil
+		}
+	}
+
+	userId, _ := claims["email"].(string)
+	if userId == "" {
+		return false, nil
+	}
+	exists, err := r.redis.Exists(ctx, revokedUserPrefix+userId)
+	if err != nil {
+		return false, fmt.Errorf("error checking user revocation : %v", err)
+	}
+	if exists == 0 {
+		return false, nil
+	}
+	val, err := r.redis.Get(ctx, revokedUserPrefix+userId)
+	if errors.Is(err, Redis.ErrKeyNotFound) {
+		// the entry expired between the two calls
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking user revocation : %v", err)
+	}
+	cutoff, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid revocation entry for %s : %v", userId, err)
+	}
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		// without iat we can't tell the token is newer than the cutoff
+		return true, nil
+	}
+	return issuedAt.Unix() < cutoff, nil
+}
+
+func ttlMinutes(ttl time.Duration) int {
+	return int(math.Ceil(ttl.Minutes()))
+}
@@ -0,0 +1,95 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/tanmaygupta069/order-service-go/config"
+	HoldingPb "github.com/tanmaygupta069/order-service-go/generated/holding"
+	OrderPb "github.com/tanmaygupta069/order-service-go/generated/order"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// forwardedHeaders are passed to the gRPC server as metadata under the same name.
+// Authorization is always forwarded by grpc-gateway itself.
+var forwardedHeaders = map[string]bool{
+	"x-api-key":      true,
+	"x-on-behalf-of": true,
+}
+
+// NewGateway returns an http.Handler translating REST/JSON calls into gRPC calls. It dials
+// the gRPC server rather than calling the controllers directly so authentication, rate
+// limits and error mapping are the same for both.
+func NewGateway(ctx context.Context, cfg *config.Config) (http.Handler, error) {
+	endpoint := cfg.ServerConfig.GrpcEndpoint
+	if endpoint == "" {
+		endpoint = "localhost:" + cfg.GrpcConfig.Port
+	}
+	creds, err := clientCredentials(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// forwarded calls are marked so the server doesn't take the gateway's own client
+	// certificate for the identity of an anonymous REST caller
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(headerMatcher), runtime.WithMetadata(auth.GatewayMetadata))
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if err = OrderPb.RegisterOrderServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return nil, fmt.Errorf("error registering order gateway : %v", err)
+	}
+	if err = HoldingPb.RegisterHoldingServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return nil, fmt.Errorf("error registering holding gateway : %v", err)
+	}
+	return mux, nil
+}
+
+func headerMatcher(key string) (string, bool) {
+	if forwardedHeaders[strings.ToLower(key)] {
+		return strings.ToLower(key), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// clientCredentials matches the gRPC server's transport, plaintext unless it has a certificate.
+func clientCredentials(cfg *config.Config, endpoint string) (credentials.TransportCredentials, error) {
+	if cfg.TlsConfig.CertFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grpc endpoint %q : %v", endpoint, err)
+	}
+	tlsConfig := &tls.Config{
+		ServerName: host,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cfg.ServerConfig.GrpcCaFile != "" {
+		bundle, err := os.ReadFile(cfg.ServerConfig.GrpcCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading grpc ca bundle : %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.ServerConfig.GrpcCaFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ServerConfig.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ServerConfig.ClientCertFile, cfg.ServerConfig.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading gateway client certificate : %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
@@ -0,0 +1,69 @@
+package money
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ROUND_HALF_EVEN = "half_even"
+	ROUND_HALF_UP   = "half_up"
+	ROUND_DOWN      = "down"
+)
+
+// Currency is how amounts in one currency are rounded, Scale is the number of decimals.
+type Currency struct {
+	Scale    int32
+	Rounding string
+}
+
+// currencies uses banker's rounding so rounding errors don't pile up in one direction
+// over many orders. Currencies missing here are rounded like defaultCurrency.
+var currencies = map[string]Currency{
+	"USD": {Scale: 2, Rounding: ROUND_HALF_EVEN},
+	"EUR": {Scale: 2, Rounding: ROUND_HALF_EVEN},
+	"GBP": {Scale: 2, Rounding: ROUND_HALF_EVEN},
+	"INR": {Scale: 2, Rounding: ROUND_HALF_EVEN},
+	"CAD": {Scale: 2, Rounding: ROUND_HALF_EVEN},
+	"JPY": {Scale: 0, Rounding: ROUND_HALF_EVEN},
+}
+
+var defaultCurrency = Currency{Scale: 2, Rounding: ROUND_HALF_EVEN}
+
+func CurrencyOf(code string) Currency {
+	if currency, ok := currencies[strings.ToUpper(code)]; ok {
+		return currency
+	}
+	return defaultCurrency
+}
+
+// Scales returns the scale of every known currency, for rounding in SQL.
+func Scales() map[string]int32 {
+	scales := make(map[string]int32, len(currencies))
+	for code, currency := range currencies {
+		scales[code] = currency.Scale
+	}
+	return scales
+}
+
+func DefaultScale() int32 {
+	return defaultCurrency.Scale
+}
+
+// Round rounds amount to the scale of currency using its rounding mode.
+func Round(amount decimal.Decimal, code string) decimal.Decimal {
+	currency := CurrencyOf(code)
+	switch currency.Rounding {
+	case ROUND_HALF_UP:
+		return amount.Round(currency.Scale)
+	case ROUND_DOWN:
+		return amount.Truncate(currency.Scale)
+	}
+	return amount.RoundBank(currency.Scale)
+}
+
+// Total is price times quantity rounded for currency.
+func Total(price decimal.Decimal, quantity int32, code string) decimal.Decimal {
+	return Round(price.Mul(decimal.NewFromInt32(quantity)), code)
+}
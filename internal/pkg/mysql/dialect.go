@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// Dialect is what differs between the databases SqlServiceImplementation runs on. Queries
+// built with Query stick to SQL both understand, the rest lives here and in the dialect's
+// own directory of migrations.
+type Dialect interface {
+	// Name is also the directory of the dialect's migrations and gorm's dialector name.
+	Name() string
+	Dialector(cfg config.MySqlConfig) gorm.Dialector
+	// Configure tunes the connection pool once the database is open.
+	Configure(sqlDB *sql.DB)
+	// Ephemeral reports whether the database only lives as long as the process, in which
+	// case nothing else can migrate it.
+	Ephemeral(cfg config.MySqlConfig) bool
+}
+
+// DialectFor returns the dialect of driver, mysql when it is empty.
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case "", config.DRIVER_MYSQL:
+		return mysqlDialect{}, nil
+	case config.DRIVER_SQLITE:
+		return sqliteDialect{}, nil
+	}
+	return nil, fmt.Errorf("unknown database driver %s", driver)
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return config.DRIVER_MYSQL
+}
+
+// Dialector builds the DSN, parseTime makes datetime columns scan into time.Time.
+func (mysqlDialect) Dialector(cfg config.MySqlConfig) gorm.Dialector {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+	return mysql.Open(dsn)
+}
+
+func (mysqlDialect) Configure(sqlDB *sql.DB) {}
+
+func (mysqlDialect) Ephemeral(cfg config.MySqlConfig) bool {
+	return false
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string {
+	return config.DRIVER_SQLITE
+}
+
+// Dialector opens the file at SqlitePath, waiting for locks instead of failing at once
+// and enforcing foreign keys, which SQLite leaves off by default.
+func (sqliteDialect) Dialector(cfg config.MySqlConfig) gorm.Dialector {
+	separator := "?"
+	if strings.Contains(cfg.SqlitePath, "?") {
+		separator = "&"
+	}
+	return sqlite.Open(cfg.SqlitePath + separator + "_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+}
+
+// Configure keeps a single connection. SQLite takes one writer at a time anyway, and an
+// in-memory database is private to its connection, so a second one would see no tables.
+func (sqliteDialect) Configure(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+}
+
+func (sqliteDialect) Ephemeral(cfg config.MySqlConfig) bool {
+	return cfg.SqlitePath == ":memory:" || strings.Contains(cfg.SqlitePath, "mode=memory")
+}
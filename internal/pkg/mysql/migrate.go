@@ -0,0 +1,270 @@
+package mysql
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds a directory of migrations per dialect, named after the dialect.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+// migrationName matches the migration file names, 0001_initial_schema.up.sql.
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version bigint NOT NULL,
+	name varchar(255) NOT NULL,
+	applied_at datetime NOT NULL,
+	PRIMARY KEY (version)
+)`
+
+// Migration is one versioned schema change, read from a pair of up and down files or
+// written in Go when it depends on the schema it finds.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// UpFunc and DownFunc run in place of Up and Down for migrations written in Go.
+	UpFunc   func(tx *gorm.DB) error
+	DownFunc func(tx *gorm.DB) error
+}
+
+// MigrationState is a migration along with when it was applied, nil when it is pending.
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrations returns the embedded migrations of dialect and the Go migrations in version
+// order.
+func Migrations(dialect string) ([]*Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := migrationFiles.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s : %v", entry.Name(), err)
+		}
+		data, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d is named both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+	migrations := make([]*Migration, 0, len(byVersion)+len(goMigrations))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	for _, migration := range goMigrations {
+		if _, ok := byVersion[migration.Version]; ok {
+			return nil, fmt.Errorf("migration %d is both a file and a Go migration", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrationStatus lists every migration and whether it has been applied.
+func MigrationStatus(ctx context.Context, d *gorm.DB) ([]*MigrationState, error) {
+	migrations, err := Migrations(d.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]*MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		state := &MigrationState{Migration: *migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// MigrateUp applies every pending migration in version order and returns the ones it
+// applied. It stops at the first one that fails.
+func MigrateUp(ctx context.Context, d *gorm.DB) ([]*Migration, error) {
+	migrations, err := Migrations(d.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err := runMigration(ctx, d, migration.up, func(tx *gorm.DB) error {
+			return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)", migration.Version, migration.Name, time.Now().UTC()).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("error applying migration %d_%s : %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// MigrateDown rolls back the last steps applied migrations, newest first, and returns
+// the ones it rolled back.
+func MigrateDown(ctx context.Context, d *gorm.DB, steps int) ([]*Migration, error) {
+	migrations, err := Migrations(d.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedMigrations(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	done := make([]*Migration, 0)
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		err := runMigration(ctx, d, migration.down, func(tx *gorm.DB) error {
+			return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("error rolling back migration %d_%s : %v", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// appliedMigrations returns when each applied version was applied, nothing is applied
+// before the schema_migrations table exists.
+func appliedMigrations(ctx context.Context, d *gorm.DB) (map[int64]time.Time, error) {
+	if !d.WithContext(ctx).Migrator().HasTable("schema_migrations") {
+		return make(map[int64]time.Time), nil
+	}
+	rows := make([]struct {
+		Version   int64
+		AppliedAt time.Time
+	}, 0)
+	if err := d.WithContext(ctx).Raw("SELECT version, applied_at FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations : %v", err)
+	}
+	applied := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+func (m *Migration) up(tx *gorm.DB) error {
+	if m.UpFunc != nil {
+		return m.UpFunc(tx)
+	}
+	return runScript(tx, m.Up)
+}
+
+func (m *Migration) down(tx *gorm.DB) error {
+	if m.DownFunc != nil {
+		return m.DownFunc(tx)
+	}
+	return runScript(tx, m.Down)
+}
+
+// runMigration runs change and then record in one transaction, creating schema_migrations
+// on first use. MySQL commits DDL implicitly, so a failed schema change can leave earlier
+// statements applied.
+func runMigration(ctx context.Context, d *gorm.DB, change func(tx *gorm.DB) error, record func(tx *gorm.DB) error) error {
+	if err := d.WithContext(ctx).Exec(createSchemaMigrations).Error; err != nil {
+		return fmt.Errorf("error creating schema_migrations : %v", err)
+	}
+	return d.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := change(tx); err != nil {
+			return err
+		}
+		return record(tx)
+	})
+}
+
+func runScript(tx *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits script on the semicolons ending a line and drops -- comments,
+// since the driver runs a single statement per call.
+func splitStatements(script string) []string {
+	statements := make([]string, 0)
+	var current strings.Builder
+	for _, line := range strings.Split(strings.ReplaceAll(script, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+func pendingMigrations(states []*MigrationState) int {
+	pending := 0
+	for _, state := range states {
+		if state.AppliedAt == nil {
+			pending++
+		}
+	}
+	return pending
+}
@@ -0,0 +1,165 @@
+package mysql
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+// baselineOrders and baselineHoldings are the models AutoMigrate created the schema from
+// before migrations, at the baseline commit.
+type baselineOrders struct {
+	OrderId       string `gorm:"primaryKey"`
+	UserId        string
+	Symbol        string
+	PricePerStock float64
+	Quantity      int32
+	TotalPrice    float64
+	OrderType     string
+	OrderStatus   string
+}
+
+func (baselineOrders) TableName() string {
+	return "orders"
+}
+
+type baselineHoldings struct {
+	UserId     string `gorm:"primaryKey"`
+	Symbol     string `gorm:"primaryKey"`
+	Quantity   int32
+	TotalPrice float64
+}
+
+func (baselineHoldings) TableName() string {
+	return "holdings"
+}
+
+// baselineSecurities is the security master as AutoMigrate created it, a database could
+// have it along with the baseline orders.
+type baselineSecurities struct {
+	Symbol   string `gorm:"primaryKey"`
+	Name     string
+	Exchange string
+	Currency string
+	LotSize  int32
+	TickSize float64
+	Tradable bool
+	Sector   string
+}
+
+func (baselineSecurities) TableName() string {
+	return "securities"
+}
+
+func TestMigrateUpAdoptsAutoMigrateSchema(t *testing.T) {
+	ctx := context.Background()
+	// a file database is left for the migrate command, unlike an in-memory one
+	d, err := OpenDatabase(ctx, config.MySqlConfig{
+		Driver:     config.DRIVER_SQLITE,
+		SqlitePath: filepath.Join(t.TempDir(), "baseline.db"),
+	})
+	if err != nil {
+		t.Fatalf("error opening test database : %v", err)
+	}
+	if err := d.AutoMigrate(&baselineOrders{}, &baselineHoldings{}, &baselineSecurities{}); err != nil {
+		t.Fatalf("error creating the baseline schema : %v", err)
+	}
+	seed := []interface{}{
+		&baselineSecurities{Symbol: "7203", Currency: "JPY"},
+		&baselineSecurities{Symbol: "AAPL", Currency: "USD"},
+		&baselineOrders{OrderId: "o1", UserId: "u1", Symbol: "AAPL", PricePerStock: 10.125, Quantity: 3, TotalPrice: 30.375, OrderType: "BUY", OrderStatus: "placed"},
+		&baselineOrders{OrderId: "o2", UserId: "u1", Symbol: "7203", PricePerStock: 2500.6, Quantity: 1, TotalPrice: 2500.6, OrderType: "BUY", OrderStatus: "completed"},
+		&baselineOrders{OrderId: "o3", UserId: "u1", Symbol: "UNKNOWN", PricePerStock: 1.006, Quantity: 1, TotalPrice: 1.006, OrderType: "BUY", OrderStatus: "placed"},
+		&baselineHoldings{UserId: "u1", Symbol: "7203", Quantity: 1, TotalPrice: 2500.6},
+	}
+	for _, row := range seed {
+		if err := d.Create(row).Error; err != nil {
+			t.Fatalf("error seeding the baseline schema : %v", err)
+		}
+	}
+
+	applied, err := MigrateUp(ctx, d)
+	if err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	if len(applied) != 4 {
+		t.Fatalf("MigrateUp() applied %d migrations, want 4", len(applied))
+	}
+
+	for _, index := range []string{"idx_orders_user_id_created_at", "idx_orders_status_order_id"} {
+		if !d.Migrator().HasIndex(&Orders{}, index) {
+			t.Errorf("index %s is missing", index)
+		}
+	}
+	for _, table := range []interface{}{&OrderEvents{}, &AuditLogs{}, &ApiKeys{}} {
+		if !d.Migrator().HasTable(table) {
+			t.Errorf("table of %T is missing", table)
+		}
+	}
+
+	// the history query of the order repository, a keyset on (created_at, order_id)
+	orders, err := NewSqlClientFor[Orders](d).GetAll(ctx, Where(
+		Eq("user_id", "u1"),
+		RowLt([]string{"created_at", "order_id"}, time.Now().Add(time.Hour), "~"),
+	).OrderBy("created_at", true).OrderBy("order_id", true))
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	want := map[string]string{"o1": "10.13", "o2": "2501", "o3": "1.01"}
+	if len(orders) != len(want) {
+		t.Fatalf("GetAll() = %d orders, want %d", len(orders), len(want))
+	}
+	for _, order := range orders {
+		if got := order.PricePerStock; !got.Equal(decimal.RequireFromString(want[order.OrderId])) {
+			t.Errorf("price of %s = %s, want %s", order.OrderId, got, want[order.OrderId])
+		}
+		if order.TimeInForce != "GTC" {
+			t.Errorf("time in force of %s = %s, want GTC", order.OrderId, order.TimeInForce)
+		}
+		if order.CreatedAt.IsZero() {
+			t.Errorf("created at of %s is not set", order.OrderId)
+		}
+	}
+
+	var holding Holdings
+	if err := d.First(&holding, "user_id = ? AND symbol = ?", "u1", "7203").Error; err != nil {
+		t.Fatalf("error reading holding : %v", err)
+	}
+	if !holding.TotalPrice.Equal(decimal.NewFromInt(2501)) {
+		t.Errorf("holding total = %s, want 2501", holding.TotalPrice)
+	}
+
+	if _, err := MigrateDown(ctx, d, 3); err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+	if _, err := MigrateUp(ctx, d); err != nil {
+		t.Fatalf("MigrateUp() after MigrateDown() error = %v", err)
+	}
+}
+
+func TestMigrateUpIsANoOpOnCurrentSchema(t *testing.T) {
+	ctx := context.Background()
+	d := openTestDatabase(t)
+
+	order := &Orders{OrderId: "o1", UserId: "u1", Symbol: "AAPL", PricePerStock: decimal.RequireFromString("10.1234"), TimeInForce: "DAY", CreatedAt: time.Now()}
+	if err := d.Create(order).Error; err != nil {
+		t.Fatalf("error creating order : %v", err)
+	}
+	if _, err := MigrateDown(ctx, d, 3); err != nil {
+		t.Fatalf("MigrateDown() error = %v", err)
+	}
+	if _, err := MigrateUp(ctx, d); err != nil {
+		t.Fatalf("MigrateUp() error = %v", err)
+	}
+	var got Orders
+	if err := d.First(&got, "order_id = ?", "o1").Error; err != nil {
+		t.Fatalf("error reading order : %v", err)
+	}
+	if got.TimeInForce != "DAY" || !got.PricePerStock.Equal(order.PricePerStock) {
+		t.Errorf("order = %s %s, want it unchanged", got.TimeInForce, got.PricePerStock)
+	}
+}
@@ -0,0 +1,163 @@
+package mysql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// goMigrations are the migrations of every dialect which depend on the schema they find,
+// databases AutoMigrate last changed can be at any point between the baseline and 0001.
+var goMigrations = []*Migration{
+	{
+		Version:  2,
+		Name:     "adopt_automigrate_schema",
+		UpFunc:   adoptAutoMigrateSchema,
+		DownFunc: func(tx *gorm.DB) error { return nil },
+	},
+	{
+		Version:  3,
+		Name:     "decimal_money",
+		UpFunc:   decimalMoney,
+		DownFunc: floatMoney,
+	},
+}
+
+// adoptedOrderColumns are the columns orders got after the baseline, with their type in
+// MySQL and in SQLite. The tables added since are created by 0001.
+var adoptedOrderColumns = []struct {
+	name       string
+	mysqlType  string
+	sqliteType string
+}{
+	{"allow_pre_market", "boolean", "numeric"},
+	{"allow_after_hours", "boolean", "numeric"},
+	{"time_in_force", "varchar(191) DEFAULT 'DAY'", "text DEFAULT 'DAY'"},
+	{"created_at", "datetime(3)", "datetime"},
+	{"updated_at", "datetime(3)", "datetime"},
+	{"completed_at", "datetime(3)", "datetime"},
+	{"cancelled_at", "datetime(3)", "datetime"},
+}
+
+// adoptAutoMigrateSchema adds the orders columns missing from a database AutoMigrate
+// created, which 0001 leaves alone as the table exists. Orders from before time in force
+// never expired, so they become GTC, and orders without a creation time get the time of
+// the migration so history pages, which compare created_at, still reach them. Down keeps
+// the columns, they are part of the 0001 schema.
+func adoptAutoMigrateSchema(tx *gorm.DB) error {
+	for _, column := range adoptedOrderColumns {
+		if tx.Migrator().HasColumn("orders", column.name) {
+			continue
+		}
+		columnType := column.mysqlType
+		if tx.Dialector.Name() == config.DRIVER_SQLITE {
+			columnType = column.sqliteType
+		}
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE orders ADD COLUMN %s %s", column.name, columnType)).Error; err != nil {
+			return fmt.Errorf("error adding orders.%s : %v", column.name, err)
+		}
+		if column.name == "time_in_force" {
+			if err := tx.Exec("UPDATE orders SET time_in_force = 'GTC'").Error; err != nil {
+				return fmt.Errorf("error setting time in force of existing orders : %v", err)
+			}
+		}
+	}
+	now := time.Now().UTC()
+	if err := tx.Exec("UPDATE orders SET created_at = ?, updated_at = ? WHERE created_at IS NULL", now, now).Error; err != nil {
+		return fmt.Errorf("error setting creation time of existing orders : %v", err)
+	}
+	return nil
+}
+
+// moneyColumns are the amount columns which used to be doubles, by table.
+var moneyColumns = map[string][]string{
+	"orders":   {"price_per_stock", "total_price"},
+	"holdings": {"total_price"},
+}
+
+// decimalMoney turns the double amounts of the baseline into decimals rounded to the
+// scale of the currency the symbol is quoted in, so old rows match what the service
+// writes now. Amounts which already are decimals are left alone. SQLite columns take any
+// type, there only the amounts are rounded.
+func decimalMoney(tx *gorm.DB) error {
+	float, err := hasFloatMoney(tx)
+	if err != nil || !float {
+		return err
+	}
+	if tx.Dialector.Name() == config.DRIVER_MYSQL {
+		if err := alterMoney(tx, "decimal(19,4)"); err != nil {
+			return err
+		}
+	}
+	return roundMoney(tx)
+}
+
+// floatMoney turns the amounts back into the doubles of the baseline.
+func floatMoney(tx *gorm.DB) error {
+	if tx.Dialector.Name() != config.DRIVER_MYSQL {
+		return nil
+	}
+	return alterMoney(tx, "double")
+}
+
+// hasFloatMoney reports whether the amount columns still have the pre decimal float type.
+func hasFloatMoney(tx *gorm.DB) (bool, error) {
+	columns, err := tx.Migrator().ColumnTypes("orders")
+	if err != nil {
+		return false, fmt.Errorf("error reading the columns of orders : %v", err)
+	}
+	for _, column := range columns {
+		if column.Name() == "price_per_stock" {
+			name := strings.ToLower(column.DatabaseTypeName())
+			return name == "double" || name == "float" || name == "real", nil
+		}
+	}
+	return false, nil
+}
+
+func alterMoney(tx *gorm.DB, columnType string) error {
+	for table, columns := range moneyColumns {
+		modify := make([]string, 0, len(columns))
+		for _, column := range columns {
+			modify = append(modify, fmt.Sprintf("MODIFY %s %s", column, columnType))
+		}
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s %s", table, strings.Join(modify, ", "))).Error; err != nil {
+			return fmt.Errorf("error changing the amounts of %s to %s : %v", table, columnType, err)
+		}
+	}
+	return nil
+}
+
+// roundMoney rounds the amounts to the scale of the currency the symbol is quoted in,
+// symbols missing from the security master get the default scale.
+func roundMoney(tx *gorm.DB) error {
+	scales := money.Scales()
+	codes := make([]string, 0, len(scales))
+	for code := range scales {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for table, columns := range moneyColumns {
+		var scale strings.Builder
+		scale.WriteString("COALESCE((SELECT CASE UPPER(s.currency)")
+		for _, code := range codes {
+			fmt.Fprintf(&scale, " WHEN '%s' THEN %d", code, scales[code])
+		}
+		fmt.Fprintf(&scale, " ELSE %d END FROM securities s WHERE s.symbol = %s.symbol), %d)", money.DefaultScale(), table, money.DefaultScale())
+
+		sets := make([]string, 0, len(columns))
+		for _, column := range columns {
+			sets = append(sets, fmt.Sprintf("%s = ROUND(%s, %s)", column, column, scale.String()))
+		}
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s", table, strings.Join(sets, ", "))).Error; err != nil {
+			return fmt.Errorf("error rounding %s : %v", table, err)
+		}
+	}
+	return nil
+}
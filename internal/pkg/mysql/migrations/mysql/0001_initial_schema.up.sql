@@ -0,0 +1,83 @@
+-- The schema of a new database: orders with their time in force and lifecycle timestamps,
+-- money as decimal(19,4), and the order_events, audit_logs and api_keys tables. It is not
+-- what AutoMigrate built, those databases lack the newer order columns and keep money in
+-- doubles. Every statement is IF NOT EXISTS so such a database only gets the tables it is
+-- missing, 0002 adds the missing columns and 0003 converts the amounts.
+
+CREATE TABLE IF NOT EXISTS orders (
+	order_id varchar(191) NOT NULL,
+	user_id longtext,
+	symbol longtext,
+	price_per_stock decimal(19,4),
+	quantity int,
+	total_price decimal(19,4),
+	order_type longtext,
+	order_status longtext,
+	allow_pre_market boolean,
+	allow_after_hours boolean,
+	time_in_force varchar(191) DEFAULT 'DAY',
+	created_at datetime(3),
+	updated_at datetime(3),
+	completed_at datetime(3),
+	cancelled_at datetime(3),
+	PRIMARY KEY (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_events (
+	id bigint unsigned NOT NULL AUTO_INCREMENT,
+	order_id varchar(36),
+	from_status longtext,
+	to_status longtext,
+	actor longtext,
+	reason longtext,
+	created_at datetime(3),
+	PRIMARY KEY (id),
+	INDEX idx_order_events_order_id (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS holdings (
+	user_id varchar(191) NOT NULL,
+	symbol varchar(191) NOT NULL,
+	quantity int,
+	total_price decimal(19,4),
+	PRIMARY KEY (user_id, symbol)
+);
+
+CREATE TABLE IF NOT EXISTS securities (
+	symbol varchar(191) NOT NULL,
+	name longtext,
+	exchange longtext,
+	currency longtext,
+	lot_size int,
+	tick_size double,
+	tradable boolean,
+	sector longtext,
+	PRIMARY KEY (symbol)
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id bigint unsigned NOT NULL AUTO_INCREMENT,
+	actor longtext,
+	roles longtext,
+	method longtext,
+	request text,
+	outcome longtext,
+	error text,
+	created_at datetime(3),
+	PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id varchar(191) NOT NULL,
+	prefix varchar(16),
+	hash longtext,
+	name longtext,
+	created_by longtext,
+	scopes longtext,
+	expires_at datetime(3),
+	last_used_at datetime(3),
+	revoked_at datetime(3),
+	created_at datetime(3),
+	PRIMARY KEY (id),
+	UNIQUE INDEX idx_api_keys_prefix (prefix)
+);
@@ -0,0 +1,7 @@
+DROP INDEX idx_orders_status_order_id ON orders;
+
+DROP INDEX idx_orders_user_id_created_at ON orders;
+
+ALTER TABLE orders
+	MODIFY user_id longtext,
+	MODIFY order_status longtext;
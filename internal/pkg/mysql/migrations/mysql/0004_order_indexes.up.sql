@@ -0,0 +1,13 @@
+-- Indexes for the order lookups that run on every request or fill. The filtered columns
+-- were longtext, which MySQL can't index without a prefix, so they get a bounded size.
+-- holdings needs nothing, its primary key already is (user_id, symbol).
+
+ALTER TABLE orders
+	MODIFY user_id varchar(191),
+	MODIFY order_status varchar(32);
+
+-- order history and GetOrders, by user in (created_at, order_id) order
+CREATE INDEX idx_orders_user_id_created_at ON orders (user_id, created_at, order_id);
+
+-- the fill path, a placed order at or after a random order_id
+CREATE INDEX idx_orders_status_order_id ON orders (order_status, order_id);
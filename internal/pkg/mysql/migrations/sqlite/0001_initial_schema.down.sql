@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS audit_logs;
+DROP TABLE IF EXISTS securities;
+DROP TABLE IF EXISTS holdings;
+DROP TABLE IF EXISTS order_events;
+DROP TABLE IF EXISTS orders;
@@ -0,0 +1,79 @@
+-- The SQLite version of the MySQL 0001, with the same tables, columns and indexes.
+
+CREATE TABLE IF NOT EXISTS orders (
+	order_id text NOT NULL,
+	user_id text,
+	symbol text,
+	price_per_stock decimal(19,4),
+	quantity integer,
+	total_price decimal(19,4),
+	order_type text,
+	order_status text,
+	allow_pre_market numeric,
+	allow_after_hours numeric,
+	time_in_force text DEFAULT 'DAY',
+	created_at datetime,
+	updated_at datetime,
+	completed_at datetime,
+	cancelled_at datetime,
+	PRIMARY KEY (order_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_events (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	order_id text,
+	from_status text,
+	to_status text,
+	actor text,
+	reason text,
+	created_at datetime
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_order_id ON order_events (order_id);
+
+CREATE TABLE IF NOT EXISTS holdings (
+	user_id text NOT NULL,
+	symbol text NOT NULL,
+	quantity integer,
+	total_price decimal(19,4),
+	PRIMARY KEY (user_id, symbol)
+);
+
+CREATE TABLE IF NOT EXISTS securities (
+	symbol text NOT NULL,
+	name text,
+	exchange text,
+	currency text,
+	lot_size integer,
+	tick_size real,
+	tradable numeric,
+	sector text,
+	PRIMARY KEY (symbol)
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	actor text,
+	roles text,
+	method text,
+	request text,
+	outcome text,
+	error text,
+	created_at datetime
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id text NOT NULL,
+	prefix text,
+	hash text,
+	name text,
+	created_by text,
+	scopes text,
+	expires_at datetime,
+	last_used_at datetime,
+	revoked_at datetime,
+	created_at datetime,
+	PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
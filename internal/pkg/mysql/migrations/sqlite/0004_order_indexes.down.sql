@@ -0,0 +1,3 @@
+DROP INDEX idx_orders_status_order_id;
+
+DROP INDEX idx_orders_user_id_created_at;
@@ -0,0 +1,8 @@
+-- Indexes for the order lookups that run on every request or fill, SQLite columns need
+-- no resizing first.
+
+-- order history and GetOrders, by user in (created_at, order_id) order
+CREATE INDEX idx_orders_user_id_created_at ON orders (user_id, created_at, order_id);
+
+-- the fill path, a placed order at or after a random order_id
+CREATE INDEX idx_orders_status_order_id ON orders (order_status, order_id);
@@ -1,19 +1,81 @@
-package mysql
-
-type Orders struct {
-	OrderId       string `gorm:"primaryKey"`
-	UserId        string
-	Symbol        string
-	PricePerStock float64
-	Quantity      int32
-	TotalPrice    float64
-	OrderType     string
-	OrderStatus   string
-}
-
-type Holdings struct {
-	UserId     string `gorm:"primaryKey"`
-	Symbol     string `gorm:"primaryKey"`
-	Quantity   int32
-	TotalPrice float64
-}
+package mysql
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Orders is indexed for the history of a user and for picking placed orders to fill, see
+// migration 0004_order_indexes.
+type Orders struct {
+	OrderId       string `gorm:"primaryKey;index:idx_orders_user_id_created_at,priority:3;index:idx_orders_status_order_id,priority:2"`
+	UserId        string `gorm:"size:191;index:idx_orders_user_id_created_at,priority:1"`
+	Symbol        string
+	PricePerStock decimal.Decimal `gorm:"type:decimal(19,4)"`
+	Quantity      int32
+	TotalPrice    decimal.Decimal `gorm:"type:decimal(19,4)"`
+	OrderType     string
+	OrderStatus   string `gorm:"size:32;index:idx_orders_status_order_id,priority:1"`
+	AllowPreMarket  bool
+	AllowAfterHours bool
+	TimeInForce     string `gorm:"default:DAY"`
+	CreatedAt       time.Time `gorm:"index:idx_orders_user_id_created_at,priority:2"`
+	UpdatedAt       time.Time
+	CompletedAt     *time.Time
+	CancelledAt     *time.Time
+}
+
+// OrderEvents is the append only log of status changes of an order, rows are never
+// updated or deleted.
+type OrderEvents struct {
+	Id         uint64 `gorm:"primaryKey;autoIncrement"`
+	OrderId    string `gorm:"index;size:36"`
+	FromStatus string
+	ToStatus   string
+	Actor      string
+	Reason     string
+	CreatedAt  time.Time
+}
+
+type Holdings struct {
+	UserId     string `gorm:"primaryKey"`
+	Symbol     string `gorm:"primaryKey"`
+	Quantity   int32
+	TotalPrice decimal.Decimal `gorm:"type:decimal(19,4)"`
+}
+
+type Securities struct {
+	Symbol   string `gorm:"primaryKey"`
+	Name     string
+	Exchange string
+	Currency string
+	LotSize  int32
+	TickSize float64
+	Tradable bool
+	Sector   string
+}
+
+type AuditLogs struct {
+	Id        uint64 `gorm:"primaryKey;autoIncrement"`
+	Actor     string
+	Roles     string
+	Method    string
+	Request   string `gorm:"type:text"`
+	Outcome   string
+	Error     string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+type ApiKeys struct {
+	Id         string `gorm:"primaryKey"`
+	Prefix     string `gorm:"uniqueIndex;size:16"`
+	Hash       string
+	Name       string
+	CreatedBy  string
+	Scopes     string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -1,125 +1,205 @@
-package mysql
-
-import (
-	"fmt"
-	"sync"
-
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/tanmaygupta069/order-service-go/config"
-)
-
-var db *gorm.DB
-
-var once sync.Once
-
-type SqlServiceImplementation[T any] struct {
-	db *gorm.DB
-}
-
-func NewSqlClient[T any]()*SqlServiceImplementation[T] {
-	return &SqlServiceImplementation[T]{
-		db: GetSqlClient(),
-	}
-}
-
-func InitializeSqlClient() {
-	once.Do(func() {
-		// fmt.Print("in initialize")
-		cfg, er := config.GetConfig()
-		if er != nil {
-			fmt.Println("error occured in sql client init")
-		}
-		dbConfig := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", cfg.MySqlConfig.User, cfg.MySqlConfig.Password, cfg.MySqlConfig.Host, cfg.MySqlConfig.Port, cfg.MySqlConfig.Database)
-		d, err := gorm.Open(mysql.Open(dbConfig), &gorm.Config{})
-		if err != nil {
-			fmt.Println("error occured while connecting to mysql")
-		}
-		sqlDB, err := d.DB()
-		if err != nil {
-			fmt.Println("Failed to get DB instance:", err)
-			return
-		}
-		if err := sqlDB.Ping(); err != nil {
-			fmt.Println("Failed to ping DB:", err)
-			return
-		}
-		if err := d.AutoMigrate(&Orders{}, &Holdings{}); err != nil {
-			fmt.Println("Failed to auto-migrate:", err)
-			return
-		}
-
-		fmt.Println("DB connection successful")
-		db = d
-	})
-}
-
-func GetSqlClient() *gorm.DB {
-	if db == nil {
-		InitializeSqlClient()
-	}
-	return db
-}
-
-func (s *SqlServiceImplementation[T]) GetOne(filters map[string]interface{}) (*T, error) {
-	var entity T
-	query := s.db
-	for key, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	if err := query.First(&entity).Error; err != nil {
-		return nil, err
-	}
-	return &entity, nil
-}
-
-// ✅ Get all records using a specific filter
-func (s *SqlServiceImplementation[T]) GetAll(filters map[string]interface{}) ([]T, error) {
-	var entities []T
-	query := s.db
-	for key, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	if err := query.Find(&entities).Error; err != nil {
-		return nil, err
-	}
-	return entities, nil
-}
-
-// ✅ Update a record
-func (s *SqlServiceImplementation[T]) Update(data *T) error {
-	return s.db.Save(data).Error
-}
-
-// ✅ Get one record randomly based on a filter
-func (s *SqlServiceImplementation[T]) GetOneRandomly(filters map[string]interface{}) (*T, error) {
-	var entity T
-	query := s.db
-	for key, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-
-	err := query.Order("RAND()").Limit(1).First(&entity).Error
-	if err != nil {
-		return nil, err
-	}
-	return &entity, nil
-}
-
-func (s *SqlServiceImplementation[T]) Insert(data *T) error {
-	return s.db.Create(data).Error
-}
-
-func (s *SqlServiceImplementation[T]) Delete(filters map[string]interface{}) error {
-	var entity T
-	query := s.db
-	for key, value := range filters {
-		query = query.Where(fmt.Sprintf("%s = ?", key), value)
-	}
-	err:=query.Delete(&entity).Error
-	return err
-}
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+var cfg, _ = config.GetConfig()
+
+var db *gorm.DB
+
+var once sync.Once
+
+type SqlServiceImplementation[T any] struct {
+	db *gorm.DB
+}
+
+func NewSqlClient[T any]()*SqlServiceImplementation[T] {
+	return &SqlServiceImplementation[T]{
+		db: GetSqlClient(),
+	}
+}
+
+// NewSqlClientFor returns a client for the table of T on d rather than on the shared
+// connection.
+func NewSqlClientFor[T any](d *gorm.DB) *SqlServiceImplementation[T] {
+	return &SqlServiceImplementation[T]{
+		db: d,
+	}
+}
+
+// OpenDatabase connects to the database described by sqlCfg through its dialect. A
+// database living in this process is migrated, no migrate command can reach it.
+func OpenDatabase(ctx context.Context, sqlCfg config.MySqlConfig) (*gorm.DB, error) {
+	dialect, err := DialectFor(sqlCfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	// translated errors let the repositories report duplicates the same on every dialect
+	d, err := gorm.Open(dialect.Dialector(sqlCfg), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s : %v", dialect.Name(), err)
+	}
+	sqlDB, err := d.DB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting %s instance : %v", dialect.Name(), err)
+	}
+	dialect.Configure(sqlDB)
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("error pinging %s : %v", dialect.Name(), err)
+	}
+	if dialect.Ephemeral(sqlCfg) {
+		if _, err := MigrateUp(ctx, d); err != nil {
+			return nil, fmt.Errorf("error migrating in-memory database : %v", err)
+		}
+	}
+	return d, nil
+}
+
+func InitializeSqlClient() {
+	once.Do(func() {
+		// fmt.Print("in initialize")
+		cfg, er := config.GetConfig()
+		if er != nil {
+			fmt.Println("error occured in sql client init")
+		}
+		d, err := OpenDatabase(context.Background(), cfg.MySqlConfig)
+		if err != nil {
+			fmt.Println("error occured in sql client init:", err)
+			return
+		}
+		if states, err := MigrationStatus(context.Background(), d); err != nil {
+			// the schema is owned by the migrate command, serving only warns when it is behind
+			fmt.Println("Failed to read migration status:", err)
+		} else if pending := pendingMigrations(states); pending > 0 {
+			fmt.Printf("%d migrations are pending, run the migrate command\n", pending)
+		}
+
+		fmt.Println("DB connection successful")
+		db = d
+	})
+}
+
+func GetSqlClient() *gorm.DB {
+	if db == nil {
+		InitializeSqlClient()
+	}
+	return db
+}
+
+// GetOne returns the first row q selects, gorm.ErrRecordNotFound when there is none.
+func (s *SqlServiceImplementation[T]) GetOne(ctx context.Context, q *Query) (*T, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var entity T
+	query, err := s.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.First(&entity).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// ✅ Get all records selected by q
+func (s *SqlServiceImplementation[T]) GetAll(ctx context.Context, q *Query) ([]T, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	var entities []T
+	query, err := s.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// ✅ Update a record
+func (s *SqlServiceImplementation[T]) Update(ctx context.Context, data *T) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return s.db.WithContext(ctx).Save(data).Error
+}
+
+// GetOneRandomly returns a random row out of those q selects, the first one at or after
+// pivot in key order, wrapping around to the first row. A row's chance is the gap in key
+// before it, so pivot should be drawn from a uniformly distributed key like a uuid. Unlike
+// ORDER BY RAND() an index on the filter and key answers it without sorting every row.
+func (s *SqlServiceImplementation[T]) GetOneRandomly(ctx context.Context, q *Query, key string, pivot interface{}) (*T, error) {
+	entity, err := s.GetOne(ctx, q.clone().Where(Gte(key, pivot)).OrderBy(key, false))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.GetOne(ctx, q.clone().OrderBy(key, false))
+	}
+	return entity, err
+}
+
+// Transaction runs fn in a transaction, clients get onto it with WithTx. The timeout is
+// for the whole transaction.
+func (s *SqlServiceImplementation[T]) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return s.db.WithContext(ctx).Transaction(fn)
+}
+
+// WithTx returns a client for the same table running its queries in tx.
+func (s *SqlServiceImplementation[T]) WithTx(tx *gorm.DB) *SqlServiceImplementation[T] {
+	return &SqlServiceImplementation[T]{
+		db: tx,
+	}
+}
+
+func (s *SqlServiceImplementation[T]) Insert(ctx context.Context, data *T) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return s.db.WithContext(ctx).Create(data).Error
+}
+
+// Delete removes the rows q selects. A query without conditions is refused rather than
+// emptying the table.
+func (s *SqlServiceImplementation[T]) Delete(ctx context.Context, q *Query) error {
+	var entity T
+	if q == nil || len(q.conditions) == 0 {
+		return fmt.Errorf("refusing to delete without conditions")
+	}
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	query, err := s.query(ctx, q)
+	if err != nil {
+		return err
+	}
+	err=query.Delete(&entity).Error
+	return err
+}
+
+// query starts a statement on the table of T with q applied, checking q only uses
+// columns of T.
+func (s *SqlServiceImplementation[T]) query(ctx context.Context, q *Query) (*gorm.DB, error) {
+	columns, err := columnsOf[T](s.db)
+	if err != nil {
+		return nil, err
+	}
+	var entity T
+	return q.apply(s.db.WithContext(ctx).Model(&entity), columns)
+}
+
+// withTimeout bounds one call by the configured database timeout.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cfg == nil || cfg.TimeoutConfig.DatabaseMs <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(cfg.TimeoutConfig.DatabaseMs)*time.Millisecond)
+}
@@ -0,0 +1,233 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	opEq     = "="
+	opIn     = "IN"
+	opGt     = ">"
+	opGte    = ">="
+	opLt     = "<"
+	opLte    = "<="
+	opLike   = "LIKE"
+	opPrefix = "PREFIX"
+	opAnd    = "AND"
+	opOr     = "OR"
+)
+
+// likeEscaper escapes the LIKE wildcards of a value meant to match literally. The escape
+// character is ! since a backslash needs escaping itself in MySQL string literals.
+var likeEscaper = strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+
+// Condition is one predicate of a WHERE clause. Build it with Eq, In, Gt, Gte, Lt, Lte,
+// Like, HasPrefix, RowGt and RowLt and combine conditions with And and Or.
+type Condition struct {
+	op       string
+	columns  []string
+	values   []interface{}
+	children []Condition
+}
+
+func Eq(column string, value interface{}) Condition {
+	return Condition{op: opEq, columns: []string{column}, values: []interface{}{value}}
+}
+
+// In matches none of the rows when values is empty.
+func In(column string, values ...interface{}) Condition {
+	return Condition{op: opIn, columns: []string{column}, values: values}
+}
+
+func Gt(column string, value interface{}) Condition {
+	return Condition{op: opGt, columns: []string{column}, values: []interface{}{value}}
+}
+
+func Gte(column string, value interface{}) Condition {
+	return Condition{op: opGte, columns: []string{column}, values: []interface{}{value}}
+}
+
+func Lt(column string, value interface{}) Condition {
+	return Condition{op: opLt, columns: []string{column}, values: []interface{}{value}}
+}
+
+func Lte(column string, value interface{}) Condition {
+	return Condition{op: opLte, columns: []string{column}, values: []interface{}{value}}
+}
+
+// Like matches pattern as is, % and _ are wildcards.
+func Like(column string, pattern string) Condition {
+	return Condition{op: opLike, columns: []string{column}, values: []interface{}{pattern}}
+}
+
+// HasPrefix matches values starting with prefix, wildcards in prefix match literally.
+func HasPrefix(column string, prefix string) Condition {
+	return Condition{op: opPrefix, columns: []string{column}, values: []interface{}{likeEscaper.Replace(prefix) + "%"}}
+}
+
+// RowGt compares columns with values as a row, (a, b) > (x, y), which is what keyset
+// pagination needs.
+func RowGt(columns []string, values ...interface{}) Condition {
+	return Condition{op: opGt, columns: columns, values: values}
+}
+
+func RowLt(columns []string, values ...interface{}) Condition {
+	return Condition{op: opLt, columns: columns, values: values}
+}
+
+func And(conditions ...Condition) Condition {
+	return Condition{op: opAnd, children: conditions}
+}
+
+// Or matches none of the rows when conditions is empty.
+func Or(conditions ...Condition) Condition {
+	return Condition{op: opOr, children: conditions}
+}
+
+// sql renders the condition, refusing columns missing from columns.
+func (c Condition) sql(columns map[string]bool) (string, []interface{}, error) {
+	if c.op == opAnd || c.op == opOr {
+		if len(c.children) == 0 {
+			if c.op == opAnd {
+				return "1 = 1", nil, nil
+			}
+			return "1 = 0", nil, nil
+		}
+		parts := make([]string, 0, len(c.children))
+		args := make([]interface{}, 0)
+		for _, child := range c.children {
+			part, childArgs, err := child.sql(columns)
+			if err != nil {
+				return "", nil, err
+			}
+			parts = append(parts, "("+part+")")
+			args = append(args, childArgs...)
+		}
+		return strings.Join(parts, " "+c.op+" "), args, nil
+	}
+
+	for _, column := range c.columns {
+		if !columns[column] {
+			return "", nil, fmt.Errorf("unknown column %q", column)
+		}
+	}
+	switch {
+	case c.op == opIn:
+		if len(c.values) == 0 {
+			return "1 = 0", nil, nil
+		}
+		return fmt.Sprintf("%s IN ?", c.columns[0]), []interface{}{c.values}, nil
+	case c.op == opLike:
+		return fmt.Sprintf("%s LIKE ?", c.columns[0]), c.values, nil
+	case c.op == opPrefix:
+		return fmt.Sprintf("%s LIKE ? ESCAPE '!'", c.columns[0]), c.values, nil
+	case len(c.columns) == 0 || len(c.columns) != len(c.values):
+		return "", nil, fmt.Errorf("%d columns compared with %d values", len(c.columns), len(c.values))
+	case len(c.columns) == 1:
+		return fmt.Sprintf("%s %s ?", c.columns[0], c.op), c.values, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(c.values)), ", ")
+	return fmt.Sprintf("(%s) %s (%s)", strings.Join(c.columns, ", "), c.op, placeholders), c.values, nil
+}
+
+type orderBy struct {
+	column string
+	desc   bool
+}
+
+// Query is what the SqlServiceImplementation read and delete methods select. Conditions
+// are ANDed in the order they are given.
+type Query struct {
+	conditions []Condition
+	orderBy    []orderBy
+	limit      int
+	offset     int
+}
+
+// Where starts a query, with no conditions it selects every row.
+func Where(conditions ...Condition) *Query {
+	return &Query{
+		conditions: conditions,
+	}
+}
+
+func (q *Query) Where(conditions ...Condition) *Query {
+	q.conditions = append(q.conditions, conditions...)
+	return q
+}
+
+func (q *Query) OrderBy(column string, desc bool) *Query {
+	q.orderBy = append(q.orderBy, orderBy{column: column, desc: desc})
+	return q
+}
+
+// Limit of 0 is no limit.
+func (q *Query) Limit(limit int) *Query {
+	q.limit = limit
+	return q
+}
+
+func (q *Query) Offset(offset int) *Query {
+	q.offset = offset
+	return q
+}
+
+// clone copies the conditions of q, leaving out its ordering and paging.
+func (q *Query) clone() *Query {
+	if q == nil {
+		return Where()
+	}
+	return Where(q.conditions...)
+}
+
+// apply adds the query to db, columns is the whitelist of the model being queried.
+func (q *Query) apply(db *gorm.DB, columns map[string]bool) (*gorm.DB, error) {
+	if q == nil {
+		return db, nil
+	}
+	for _, condition := range q.conditions {
+		clause, args, err := condition.sql(columns)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(clause, args...)
+	}
+	for _, order := range q.orderBy {
+		if !columns[order.column] {
+			return nil, fmt.Errorf("unknown column %q", order.column)
+		}
+		direction := "ASC"
+		if order.desc {
+			direction = "DESC"
+		}
+		db = db.Order(fmt.Sprintf("%s %s", order.column, direction))
+	}
+	if q.limit > 0 {
+		db = db.Limit(q.limit)
+	}
+	if q.offset > 0 {
+		db = db.Offset(q.offset)
+	}
+	return db, nil
+}
+
+// schemas caches the parsed models, shared by every client.
+var schemas sync.Map
+
+// columnsOf is the column whitelist of T, the columns of its fields.
+func columnsOf[T any](db *gorm.DB) (map[string]bool, error) {
+	parsed, err := schema.Parse(new(T), &schemas, db.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]bool, len(parsed.DBNames))
+	for _, name := range parsed.DBNames {
+		columns[name] = true
+	}
+	return columns, nil
+}
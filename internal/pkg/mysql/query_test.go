@@ -0,0 +1,217 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/tanmaygupta069/order-service-go/config"
+	"gorm.io/gorm"
+)
+
+// openTestDatabase opens a migrated in-memory SQLite database private to the test.
+func openTestDatabase(t testing.TB) *gorm.DB {
+	t.Helper()
+	d, err := OpenDatabase(context.Background(), config.MySqlConfig{
+		Driver:     config.DRIVER_SQLITE,
+		SqlitePath: ":memory:",
+	})
+	if err != nil {
+		t.Fatalf("error opening test database : %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := d.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return d
+}
+
+// seedQueryOrders inserts o1 to o5, A_PL has a LIKE wildcard in it.
+func seedQueryOrders(t *testing.T) *SqlServiceImplementation[Orders] {
+	t.Helper()
+	client := NewSqlClientFor[Orders](openTestDatabase(t))
+	start := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	rows := []struct {
+		id, user, symbol, status string
+		quantity                 int32
+	}{
+		{"o1", "u1", "AAPL", "placed", 10},
+		{"o2", "u1", "MSFT", "completed", 20},
+		{"o3", "u2", "AAPL", "cancelled", 30},
+		{"o4", "u2", "A_PL", "placed", 40},
+		{"o5", "u3", "ABPL", "placed", 50},
+	}
+	for i, row := range rows {
+		order := &Orders{
+			OrderId:       row.id,
+			UserId:        row.user,
+			Symbol:        row.symbol,
+			PricePerStock: decimal.NewFromInt(100),
+			Quantity:      row.quantity,
+			TotalPrice:    decimal.NewFromInt(100 * int64(row.quantity)),
+			OrderType:     "BUY",
+			OrderStatus:   row.status,
+			TimeInForce:   "DAY",
+			CreatedAt:     start.Add(time.Duration(i) * time.Minute),
+		}
+		if err := client.Insert(context.Background(), order); err != nil {
+			t.Fatalf("error seeding %s : %v", row.id, err)
+		}
+	}
+	return client
+}
+
+func orderIds(orders []Orders) []string {
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.OrderId)
+	}
+	return ids
+}
+
+func TestQuerySelectsRows(t *testing.T) {
+	client := seedQueryOrders(t)
+	byId := func(q *Query) *Query { return q.OrderBy("order_id", false) }
+
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{"nil query selects every row", nil, []string{"o1", "o2", "o3", "o4", "o5"}},
+		{"eq", byId(Where(Eq("user_id", "u1"))), []string{"o1", "o2"}},
+		{"in", byId(Where(In("order_status", "placed", "cancelled"))), []string{"o1", "o3", "o4", "o5"}},
+		{"empty in", byId(Where(In("order_status"))), []string{}},
+		{"gt", byId(Where(Gt("quantity", 20))), []string{"o3", "o4", "o5"}},
+		{"gte", byId(Where(Gte("quantity", 20))), []string{"o2", "o3", "o4", "o5"}},
+		{"lt", byId(Where(Lt("quantity", 20))), []string{"o1"}},
+		{"lte", byId(Where(Lte("quantity", 20))), []string{"o1", "o2"}},
+		{"range", byId(Where(Gt("quantity", 10), Lt("quantity", 50))), []string{"o2", "o3", "o4"}},
+		{"like wildcards", byId(Where(Like("symbol", "A_PL"))), []string{"o1", "o3", "o4", "o5"}},
+		{"prefix matches wildcards literally", byId(Where(HasPrefix("symbol", "A_"))), []string{"o4"}},
+		{"and", byId(Where(And(Eq("user_id", "u2"), Eq("order_status", "placed")))), []string{"o4"}},
+		{"or of and", byId(Where(Or(Eq("user_id", "u1"), And(Eq("user_id", "u3"), Gt("quantity", 10))))), []string{"o1", "o2", "o5"}},
+		{"and of or", byId(Where(And(Or(Eq("symbol", "AAPL"), Eq("symbol", "MSFT")), Eq("order_status", "placed")))), []string{"o1"}},
+		{"empty and", byId(Where(And())), []string{"o1", "o2", "o3", "o4", "o5"}},
+		{"empty or", byId(Where(Or())), []string{}},
+		{"row gt", byId(Where(RowGt([]string{"user_id", "order_id"}, "u1", "o1"))), []string{"o2", "o3", "o4", "o5"}},
+		{"row lt", byId(Where(RowLt([]string{"user_id", "order_id"}, "u2", "o4"))), []string{"o1", "o2", "o3"}},
+		{"order by desc", Where().OrderBy("quantity", true), []string{"o5", "o4", "o3", "o2", "o1"}},
+		{"order by columns in turn", Where().OrderBy("user_id", true).OrderBy("order_id", false), []string{"o5", "o3", "o4", "o1", "o2"}},
+		{"limit", byId(Where()).Limit(2), []string{"o1", "o2"}},
+		{"limit and offset", byId(Where()).Limit(2).Offset(1), []string{"o2", "o3"}},
+		{"offset past the rows", byId(Where()).Limit(2).Offset(5), []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := client.GetAll(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("GetAll() error = %v", err)
+			}
+			got := orderIds(orders)
+			if tt.query == nil {
+				sort.Strings(got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryRefusesUnknownColumns(t *testing.T) {
+	client := seedQueryOrders(t)
+
+	tests := []struct {
+		name  string
+		query *Query
+	}{
+		{"eq", Where(Eq("password", "x"))},
+		{"in", Where(In("1; DROP TABLE orders", "x"))},
+		{"nested", Where(Or(Eq("user_id", "u1"), And(Gt("nope", 1))))},
+		{"row", Where(RowGt([]string{"user_id", "nope"}, "u1", "o1"))},
+		{"order by", Where().OrderBy("quantity DESC, (SELECT 1)", false)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetAll(context.Background(), tt.query)
+			if err == nil || !strings.Contains(err.Error(), "unknown column") {
+				t.Fatalf("GetAll() error = %v, want unknown column", err)
+			}
+			if err := client.Delete(context.Background(), tt.query); err == nil {
+				t.Fatalf("Delete() error = nil, want an error")
+			}
+		})
+	}
+
+	orders, err := client.GetAll(context.Background(), nil)
+	if err != nil || len(orders) != 5 {
+		t.Fatalf("GetAll() = %d rows, %v, want the 5 seeded rows", len(orders), err)
+	}
+}
+
+func TestQueryMismatchedRow(t *testing.T) {
+	client := seedQueryOrders(t)
+
+	_, err := client.GetAll(context.Background(), Where(RowGt([]string{"user_id", "order_id"}, "u1")))
+	if err == nil {
+		t.Fatalf("GetAll() error = nil, want an error for 2 columns compared with 1 value")
+	}
+}
+
+func TestDeleteRefusesQueryWithoutConditions(t *testing.T) {
+	client := seedQueryOrders(t)
+
+	for _, q := range []*Query{nil, Where(), Where().OrderBy("order_id", false).Limit(1)} {
+		if err := client.Delete(context.Background(), q); err == nil {
+			t.Fatalf("Delete(%v) error = nil, want a refusal", q)
+		}
+	}
+	if err := client.Delete(context.Background(), Where(Eq("user_id", "u1"))); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	orders, err := client.GetAll(context.Background(), Where().OrderBy("order_id", false))
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if got, want := orderIds(orders), []string{"o3", "o4", "o5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll() after delete = %v, want %v", got, want)
+	}
+}
+
+func TestGetOneRandomlyWrapsAround(t *testing.T) {
+	client := seedQueryOrders(t)
+	placed := Where(Eq("order_status", "placed"))
+
+	tests := []struct {
+		pivot string
+		want  string
+	}{
+		{"o0", "o1"},
+		{"o2", "o4"},
+		{"o4", "o4"},
+		{"o9", "o1"},
+	}
+	for _, tt := range tests {
+		order, err := client.GetOneRandomly(context.Background(), placed, "order_id", tt.pivot)
+		if err != nil {
+			t.Fatalf("GetOneRandomly(%s) error = %v", tt.pivot, err)
+		}
+		if order.OrderId != tt.want {
+			t.Errorf("GetOneRandomly(%s) = %s, want %s", tt.pivot, order.OrderId, tt.want)
+		}
+	}
+	if len(placed.orderBy) != 0 {
+		t.Errorf("GetOneRandomly() changed the ordering of the query passed in")
+	}
+
+	_, err := client.GetOneRandomly(context.Background(), Where(Eq("user_id", "nobody")), "order_id", "o1")
+	if err != gorm.ErrRecordNotFound {
+		t.Errorf("GetOneRandomly() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitInterceptor has to run after the auth interceptor so the principal is in the context.
+type RateLimitInterceptor struct {
+	limiter  RateLimiter
+	identity func(ctx context.Context) string
+}
+
+func NewRateLimitInterceptor(limiter RateLimiter) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:  limiter,
+		identity: identity,
+	}
+}
+
+// NewPeerRateLimitInterceptor limits every call by the client's address alone. It runs
+// before the auth interceptor so calls failing authentication use up tokens too.
+func NewPeerRateLimitInterceptor(limiter RateLimiter) *RateLimitInterceptor {
+	return &RateLimitInterceptor{
+		limiter:  limiter,
+		identity: peerIdentity,
+	}
+}
+
+func (i *RateLimitInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := i.limit(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *RateLimitInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := i.limit(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (i *RateLimitInterceptor) limit(ctx context.Context, method string) error {
+	allowed, retryAfter, err := i.limiter.Allow(ctx, method, i.identity(ctx))
+	if err != nil {
+		// fail open, a redis outage shouldn't take the whole api down with it
+		fmt.Printf("error checking rate limit for %s : %v\n", method, err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	seconds := strconv.Itoa(int(math.Ceil(retryAfter.Seconds())))
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", seconds))
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s, retry after %ss", method, seconds)
+}
+
+// identity is the authenticated user, or the client address for anonymous calls.
+func identity(ctx context.Context) string {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		return "user:" + principal.UserId
+	}
+	return "peer:" + clientAddress(ctx)
+}
+
+// peerIdentity keeps the buckets taken before authentication apart from the ones of
+// anonymous calls after it.
+func peerIdentity(ctx context.Context) string {
+	return "preauth:" + clientAddress(ctx)
+}
+
+// clientAddress is the host of the caller. For calls forwarded by the gateway that is the
+// address the gateway saw, which grpc-gateway appends to x-forwarded-for last.
+func clientAddress(ctx context.Context) string {
+	if auth.IsGatewayForwarded(ctx) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			hops := strings.Split(values[len(values)-1], ",")
+			if host := strings.TrimSpace(hops[len(hops)-1]); host != "" {
+				return host
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return hostOnly(p.Addr.String())
+	}
+	return "unknown"
+}
+
+// hostOnly drops the port so every connection from one client shares a bucket.
+func hostOnly(addr string) string {
+	for i := len(addr) - 1; i >= 0; i-- {
+		if addr[i] == ':' {
+			return addr[:i]
+		}
+	}
+	return addr
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+)
+
+// tokenBucketScript refills the bucket for the time since the last call and takes one
+// token. It returns {allowed, retry after in ms}. Running it in redis keeps the limit
+// consistent across replicas.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, retry}
+`
+
+// Limit is a token bucket refilled at Rate tokens per second holding at most Burst.
+type Limit struct {
+	Rate  int
+	Burst int
+}
+
+type RateLimiter interface {
+	Allow(ctx context.Context, method string, identity string) (bool, time.Duration, error)
+}
+
+type RateLimiterImp struct {
+	redis     Redis.RedisInterface
+	limit     Limit
+	overrides map[string]Limit
+}
+
+// NewRateLimiter keeps the buckets in redis, or in the process when the cache is the
+// in-memory one, which can't run the bucket script.
+func NewRateLimiter(cfg config.RateLimiterConfig) RateLimiter {
+	overrides, err := ParseOverrides(cfg.Overrides)
+	if err != nil {
+		fmt.Printf("error parsing rate limit overrides, ignoring them : %v\n", err)
+		overrides = make(map[string]Limit)
+	}
+	limit := Limit{Rate: cfg.RateLimit, Burst: cfg.BucketSize}
+	client := Redis.NewRedisClient()
+	if _, ok := client.(*Redis.MemoryRedis); ok {
+		return NewMemoryRateLimiter(limit, overrides)
+	}
+	return &RateLimiterImp{
+		redis:     client,
+		limit:     limit,
+		overrides: overrides,
+	}
+}
+
+// ParseOverrides reads entries of the form /pkg.Service/Method=rate:burst.
+func ParseOverrides(entries []string) (map[string]Limit, error) {
+	overrides := make(map[string]Limit)
+	for _, entry := range entries {
+		method, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid override %q, expected method=rate:burst", entry)
+		}
+		rate, burst, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid override %q, expected method=rate:burst", entry)
+		}
+		limit := Limit{}
+		var err error
+		if limit.Rate, err = strconv.Atoi(rate); err != nil {
+			return nil, fmt.Errorf("invalid rate in %q", entry)
+		}
+		if limit.Burst, err = strconv.Atoi(burst); err != nil {
+			return nil, fmt.Errorf("invalid burst in %q", entry)
+		}
+		overrides[strings.TrimSpace(method)] = limit
+	}
+	return overrides, nil
+}
+
+// Allow takes a token for identity. Methods with an override get a bucket of their own,
+// everything else shares the identity's default bucket.
+func (r *RateLimiterImp) Allow(ctx context.Context, method string, identity string) (bool, time.Duration, error) {
+	limit, key := bucketOf(r.limit, r.overrides, method, identity)
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return true, 0, nil
+	}
+	res, err := r.redis.Eval(ctx, tokenBucketScript, []string{key}, limit.Rate, limit.Burst, time.Now().UnixMilli())
+	if err != nil {
+		return false, 0, err
+	}
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result %v", res)
+	}
+	allowed, _ := values[0].(int64)
+	retry, _ := values[1].(int64)
+	return allowed == 1, time.Duration(retry) * time.Millisecond, nil
+}
+
+// bucketOf returns the limit and key of the bucket a call to method by identity takes its
+// token from.
+func bucketOf(limit Limit, overrides map[string]Limit, method string, identity string) (Limit, string) {
+	key := "ratelimit:" + identity
+	if override, ok := overrides[method]; ok {
+		return override, key + ":" + method
+	}
+	return limit, key
+}
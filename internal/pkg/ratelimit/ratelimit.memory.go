@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped.
+const sweepInterval = time.Minute
+
+type memoryBucket struct {
+	tokens float64
+	ts     time.Time
+	limit  Limit
+}
+
+// full reports whether the bucket has refilled by now.
+func (b *memoryBucket) full(now time.Time) bool {
+	return now.Sub(b.ts) >= time.Duration(b.limit.Burst)*time.Second/time.Duration(b.limit.Rate)
+}
+
+// MemoryRateLimiter keeps the token buckets in the process and refills them the way
+// tokenBucketScript does. Its limits only hold for the calls one replica serves.
+type MemoryRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	limit     Limit
+	overrides map[string]Limit
+	swept     time.Time
+	now       func() time.Time
+}
+
+func NewMemoryRateLimiter(limit Limit, overrides map[string]Limit) *MemoryRateLimiter {
+	return &MemoryRateLimiter{
+		buckets:   make(map[string]*memoryBucket),
+		limit:     limit,
+		overrides: overrides,
+		now:       time.Now,
+	}
+}
+
+// Allow takes a token for identity from the same bucket RateLimiterImp would use.
+func (r *MemoryRateLimiter) Allow(ctx context.Context, method string, identity string) (bool, time.Duration, error) {
+	limit, key := bucketOf(r.limit, r.overrides, method, identity)
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return true, 0, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	r.sweep(now)
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Burst), ts: now}
+		r.buckets[key] = bucket
+	}
+	bucket.limit = limit
+	elapsed := math.Max(0, float64(now.Sub(bucket.ts).Milliseconds()))
+	bucket.tokens = math.Min(float64(limit.Burst), bucket.tokens+elapsed*float64(limit.Rate)/1000)
+	bucket.ts = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+	retry := math.Ceil((1 - bucket.tokens) * 1000 / float64(limit.Rate))
+	return false, time.Duration(retry) * time.Millisecond, nil
+}
+
+// sweep drops the buckets which have refilled, a new bucket starts full anyway. The
+// script has redis expire them the same way. Callers hold mu.
+func (r *MemoryRateLimiter) sweep(now time.Time) {
+	if now.Sub(r.swept) < sweepInterval {
+		return
+	}
+	r.swept = now
+	for key, bucket := range r.buckets {
+		if bucket.full(now) {
+			delete(r.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// testClock is a MemoryRateLimiter clock moved by hand.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter(limit Limit, overrides map[string]Limit) (*MemoryRateLimiter, *testClock) {
+	clock := &testClock{now: time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)}
+	limiter := NewMemoryRateLimiter(limit, overrides)
+	limiter.now = func() time.Time { return clock.now }
+	return limiter, clock
+}
+
+func allow(t *testing.T, limiter RateLimiter, method string, identity string) (bool, time.Duration) {
+	t.Helper()
+	allowed, retry, err := limiter.Allow(context.Background(), method, identity)
+	if err != nil {
+		t.Fatalf("Allow() error = %v", err)
+	}
+	return allowed, retry
+}
+
+func TestMemoryRateLimiterBurstAndRefill(t *testing.T) {
+	limiter, clock := newTestLimiter(Limit{Rate: 2, Burst: 3}, nil)
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := allow(t, limiter, "/m", "user:a"); !allowed {
+			t.Fatalf("call %d was limited within the burst", i+1)
+		}
+	}
+	allowed, retry := allow(t, limiter, "/m", "user:a")
+	if allowed {
+		t.Fatalf("call past the burst was allowed")
+	}
+	if retry != 500*time.Millisecond {
+		t.Errorf("retry after = %s, want 500ms at 2 tokens a second", retry)
+	}
+
+	if allowed, _ := allow(t, limiter, "/m", "user:b"); !allowed {
+		t.Errorf("another identity shares the bucket")
+	}
+
+	clock.advance(250 * time.Millisecond)
+	if allowed, retry := allow(t, limiter, "/m", "user:a"); allowed || retry != 250*time.Millisecond {
+		t.Errorf("Allow() after 250ms = %v, %s, want false, 250ms", allowed, retry)
+	}
+	clock.advance(250 * time.Millisecond)
+	if allowed, _ := allow(t, limiter, "/m", "user:a"); !allowed {
+		t.Errorf("call was limited after a token refilled")
+	}
+
+	clock.advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		if allowed, _ := allow(t, limiter, "/m", "user:a"); !allowed {
+			t.Fatalf("call %d was limited after the bucket refilled", i+1)
+		}
+	}
+	if allowed, _ := allow(t, limiter, "/m", "user:a"); allowed {
+		t.Errorf("the bucket refilled past its burst")
+	}
+}
+
+func TestMemoryRateLimiterOverrides(t *testing.T) {
+	limiter, _ := newTestLimiter(Limit{Rate: 10, Burst: 1}, map[string]Limit{
+		"/slow":      {Rate: 1, Burst: 1},
+		"/unlimited": {Rate: 0, Burst: 0},
+	})
+
+	if allowed, _ := allow(t, limiter, "/fast", "user:a"); !allowed {
+		t.Fatalf("first call was limited")
+	}
+	if allowed, _ := allow(t, limiter, "/other", "user:a"); allowed {
+		t.Errorf("methods without an override don't share the default bucket")
+	}
+	if allowed, _ := allow(t, limiter, "/slow", "user:a"); !allowed {
+		t.Errorf("an overridden method shares the default bucket")
+	}
+	allowed, retry := allow(t, limiter, "/slow", "user:a")
+	if allowed || retry != time.Second {
+		t.Errorf("Allow() = %v, %s, want false, 1s", allowed, retry)
+	}
+	for i := 0; i < 5; i++ {
+		if allowed, _ := allow(t, limiter, "/unlimited", "user:a"); !allowed {
+			t.Fatalf("a method with a zero limit was limited")
+		}
+	}
+}
+
+func TestMemoryRateLimiterSweepsRefilledBuckets(t *testing.T) {
+	limiter, clock := newTestLimiter(Limit{Rate: 1, Burst: 10}, nil)
+
+	allow(t, limiter, "/m", "user:a")
+	clock.advance(5 * time.Second)
+	allow(t, limiter, "/m", "user:b")
+	clock.advance(sweepInterval)
+	allow(t, limiter, "/m", "user:c")
+
+	if _, ok := limiter.buckets["ratelimit:user:a"]; ok {
+		t.Errorf("refilled bucket was kept")
+	}
+	if len(limiter.buckets) != 1 {
+		t.Errorf("%d buckets left, want only the one just used", len(limiter.buckets))
+	}
+}
+
+func TestInterceptorLimitsWithMemoryRateLimiter(t *testing.T) {
+	limiter, _ := newTestLimiter(Limit{Rate: 1, Burst: 2}, nil)
+	interceptor := NewPeerRateLimitInterceptor(limiter).Unary()
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 4000}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.OrderService/PlaceOrder"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := interceptor(ctx, nil, info, handler); err != nil {
+			t.Fatalf("call %d error = %v", i+1, err)
+		}
+	}
+	_, err := interceptor(ctx, nil, info, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("call past the burst error = %v, want ResourceExhausted", err)
+	}
+}
@@ -0,0 +1,186 @@
+package Redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrScriptsUnsupported is returned by Eval of the in-memory client, which can't run lua.
+var ErrScriptsUnsupported = errors.New("scripts are not supported by the in-memory cache")
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryRedis is a RedisInterface kept in the process, with the same expiry semantics as
+// the redis client. Expired keys are dropped when they are next touched.
+type MemoryRedis struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+var sharedMemoryRedis *MemoryRedis
+
+var memoryOnce sync.Once
+
+// NewMemoryRedis returns an empty cache of its own, NewRedisClient hands out one shared
+// cache when CACHE_BACKEND is memory, the way every client shares one redis.
+func NewMemoryRedis() *MemoryRedis {
+	return &MemoryRedis{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+func getMemoryRedis() *MemoryRedis {
+	memoryOnce.Do(func() {
+		sharedMemoryRedis = NewMemoryRedis()
+	})
+	return sharedMemoryRedis
+}
+
+// entry returns the live entry for key, removing it if it has expired. Callers hold mu.
+func (r *MemoryRedis) entry(key string, now time.Time) (*memoryEntry, bool) {
+	e, ok := r.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if e.expired(now) {
+		delete(r.entries, key)
+		return nil, false
+	}
+	return e, true
+}
+
+func (r *MemoryRedis) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.entry(key, time.Now())
+	if !ok || e.value == "" {
+		return "", ErrKeyNotFound
+	}
+	return e.value, nil
+}
+
+// Set stores value for exp minutes, an exp of zero or less keeps the key until it is deleted.
+func (r *MemoryRedis) Set(ctx context.Context, key string, value string, exp int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := &memoryEntry{value: value}
+	if exp > 0 {
+		e.expiresAt = time.Now().Add(time.Duration(exp) * time.Minute)
+	}
+	r.entries[key] = e
+	return nil
+}
+
+func (r *MemoryRedis) Delete(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entry(key, time.Now()); !ok {
+		return 0, nil
+	}
+	delete(r.entries, key)
+	return 1, nil
+}
+
+func (r *MemoryRedis) Exists(ctx context.Context, key string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entry(key, time.Now()); !ok {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// IncrementFloat adds value to key and (re)sets its expiry in minutes.
+func (r *MemoryRedis) IncrementFloat(ctx context.Context, key string, value float64, exp int) (float64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	current := 0.0
+	if e, ok := r.entry(key, now); ok {
+		parsed, err := strconv.ParseFloat(e.value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("value of %s is not a valid float", key)
+		}
+		current = parsed
+	}
+	current += value
+	e := &memoryEntry{value: strconv.FormatFloat(current, 'f', -1, 64)}
+	if exp > 0 {
+		e.expiresAt = now.Add(time.Duration(exp) * time.Minute)
+	}
+	r.entries[key] = e
+	return current, nil
+}
+
+// Keys returns the live keys matching the redis glob pattern, sorted.
+func (r *MemoryRedis) Keys(ctx context.Context, pattern string) ([]string, error) {
+	matcher, err := globPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	keys := make([]string, 0)
+	for key := range r.entries {
+		if _, ok := r.entry(key, now); ok && matcher.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (r *MemoryRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return nil, ErrScriptsUnsupported
+}
+
+// globPattern turns a redis glob, with *, ? and [...], into an anchored regexp.
+func globPattern(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in pattern %q", pattern)
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "^") {
+				class = "^" + regexp.QuoteMeta(class[1:])
+			} else {
+				class = regexp.QuoteMeta(class)
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\-`, "-") + "]")
+			i += end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
@@ -1,87 +1,160 @@
-package Redis
-
-import (
-	"context"
-	"fmt"
-	"sync"
-	"time"
-
-	"github.com/redis/go-redis/v9"
-	"github.com/tanmaygupta069/order-service-go/config"
-)
-
-var once sync.Once
-
-var redisClient *redis.Client
-
-type RedisInterface interface {
-	Get(key string) (string, error)
-	Set(key string, value string, exp int) error
-	Delete(key string) (int64, error)
-	Exists(key string) (int64, error)
-}
-
-type RedisServiceImplementation struct {
-	redisClient *redis.Client
-}
-
-func NewRedisClient() RedisInterface {
-	return &RedisServiceImplementation{
-		redisClient: GetRedisClient(),
-	}
-}
-
-func InitializeRedisClient() {
-	once.Do(func() {
-		cfg, er := config.GetConfig()
-		if er != nil {
-			fmt.Println("an error occured.")
-		}
-		client := redis.NewClient(&redis.Options{
-			Addr:     fmt.Sprintf("%s:%s", cfg.RedisConfig.Host, cfg.RedisConfig.Port),
-			Password: cfg.RedisConfig.Password, // no password set
-			DB:       cfg.RedisConfig.Db,       // use default DB
-		})
-
-		_, err := client.Ping(context.Background()).Result()
-		if err != nil {
-			fmt.Printf("error in pinging redis client : %v", err)
-		}
-		redisClient = client
-	})
-}
-
-func (r *RedisServiceImplementation) Get(key string) (string, error) {
-	val := redisClient.Get(context.Background(), key).Val()
-	if val == "" || val == redis.Nil.Error() {
-		return "", fmt.Errorf("key not found in cache")
-	}
-	return val, nil
-}
-
-func (r *RedisServiceImplementation) Set(key string, value string, exp int) error {
-	err := redisClient.Set(context.Background(), key, value, time.Duration(exp)*time.Minute).Err()
-	if err != nil {
-		return err
-	}
-	return redisClient.Expire(context.Background(), key, time.Duration(exp)*time.Minute).Err()
-}
-
-func (r *RedisServiceImplementation) Delete(key string) (int64, error) {
-	return redisClient.Del(context.Background(), key).Result()
-}
-
-func (r *RedisServiceImplementation) Exists(key string) (int64, error) {
-	return redisClient.Exists(context.Background(), key).Result()
-}
-
-func (r *RedisServiceImplementation) Decrement(key string, field string) (int64, error) {
-	return redisClient.HIncrBy(context.Background(), key, field, -1).Result()
-}
-
-func GetRedisClient() *redis.Client {
-	if redisClient == nil {
-		InitializeRedisClient()
-	}
-	return redisClient
-}
+package Redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+var cfg, _ = config.GetConfig()
+
+var once sync.Once
+
+var redisClient *redis.Client
+
+// ErrKeyNotFound is returned by Get when the key doesn't exist or has expired. Any other
+// error means the cache couldn't be asked.
+var ErrKeyNotFound = errors.New("key not found in cache")
+
+type RedisInterface interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, exp int) error
+	Delete(ctx context.Context, key string) (int64, error)
+	Exists(ctx context.Context, key string) (int64, error)
+	IncrementFloat(ctx context.Context, key string, value float64, exp int) (float64, error)
+	Keys(ctx context.Context, pattern string) ([]string, error)
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+type RedisServiceImplementation struct {
+	redisClient *redis.Client
+}
+
+// NewRedisClient returns the shared in-memory cache instead of redis when CACHE_BACKEND
+// is memory.
+func NewRedisClient() RedisInterface {
+	if cfg != nil && cfg.StorageConfig.CacheBackend == config.BACKEND_MEMORY {
+		return getMemoryRedis()
+	}
+	return &RedisServiceImplementation{
+		redisClient: GetRedisClient(),
+	}
+}
+
+func InitializeRedisClient() {
+	once.Do(func() {
+		cfg, er := config.GetConfig()
+		if er != nil {
+			fmt.Println("an error occured.")
+		}
+		client := redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%s", cfg.RedisConfig.Host, cfg.RedisConfig.Port),
+			Password: cfg.RedisConfig.Password, // no password set
+			DB:       cfg.RedisConfig.Db,       // use default DB
+		})
+
+		_, err := client.Ping(context.Background()).Result()
+		if err != nil {
+			fmt.Printf("error in pinging redis client : %v", err)
+		}
+		redisClient = client
+	})
+}
+
+// withTimeout bounds one call by the configured redis timeout.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if cfg == nil || cfg.TimeoutConfig.RedisMs <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, time.Duration(cfg.TimeoutConfig.RedisMs)*time.Millisecond)
+}
+
+func (r *RedisServiceImplementation) Get(ctx context.Context, key string) (string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	val, err := redisClient.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrKeyNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if val == "" {
+		return "", ErrKeyNotFound
+	}
+	return val, nil
+}
+
+// Set stores value for exp minutes, an exp of zero or less keeps the key until it is deleted.
+func (r *RedisServiceImplementation) Set(ctx context.Context, key string, value string, exp int) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	if exp <= 0 {
+		return redisClient.Set(ctx, key, value, 0).Err()
+	}
+	err := redisClient.Set(ctx, key, value, time.Duration(exp)*time.Minute).Err()
+	if err != nil {
+		return err
+	}
+	return redisClient.Expire(ctx, key, time.Duration(exp)*time.Minute).Err()
+}
+
+func (r *RedisServiceImplementation) Delete(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return redisClient.Del(ctx, key).Result()
+}
+
+func (r *RedisServiceImplementation) Exists(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return redisClient.Exists(ctx, key).Result()
+}
+
+// IncrementFloat adds value to key and (re)sets its expiry in minutes.
+func (r *RedisServiceImplementation) IncrementFloat(ctx context.Context, key string, value float64, exp int) (float64, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	res, err := redisClient.IncrByFloat(ctx, key, value).Result()
+	if err != nil {
+		return 0, err
+	}
+	return res, redisClient.Expire(ctx, key, time.Duration(exp)*time.Minute).Err()
+}
+
+// Keys walks the keyspace with SCAN so it doesn't block redis the way KEYS would. The
+// timeout is for the whole walk.
+func (r *RedisServiceImplementation) Keys(ctx context.Context, pattern string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	keys := make([]string, 0)
+	iter := redisClient.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+func (r *RedisServiceImplementation) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return redisClient.Eval(ctx, script, keys, args...).Result()
+}
+
+func (r *RedisServiceImplementation) Decrement(ctx context.Context, key string, field string) (int64, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+	return redisClient.HIncrBy(ctx, key, field, -1).Result()
+}
+
+func GetRedisClient() *redis.Client {
+	if redisClient == nil {
+		InitializeRedisClient()
+	}
+	return redisClient
+}
@@ -0,0 +1,151 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+const (
+	CLIENT_AUTH_REQUIRE  = "require"
+	CLIENT_AUTH_OPTIONAL = "optional"
+)
+
+// CertReloader keeps the server certificate and client CA bundle in memory and swaps them
+// when the files change on disk, so rotated certificates are served without a restart.
+type CertReloader struct {
+	cfg      config.TlsConfig
+	mu       sync.RWMutex
+	current  *tls.Config
+	modTimes map[string]time.Time
+}
+
+func NewCertReloader(cfg config.TlsConfig) (*CertReloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE are required for tls")
+	}
+	switch strings.ToLower(cfg.ClientAuth) {
+	case "", CLIENT_AUTH_REQUIRE, CLIENT_AUTH_OPTIONAL:
+	default:
+		return nil, fmt.Errorf("unknown TLS_CLIENT_AUTH %q, expected %s or %s", cfg.ClientAuth, CLIENT_AUTH_REQUIRE, CLIENT_AUTH_OPTIONAL)
+	}
+	reloader := &CertReloader{
+		cfg:      cfg,
+		modTimes: make(map[string]time.Time),
+	}
+	if _, err := reloader.reload(); err != nil {
+		return nil, err
+	}
+	if cfg.ReloadSeconds > 0 {
+		go reloader.watch(time.Duration(cfg.ReloadSeconds) * time.Second)
+	}
+	return reloader, nil
+}
+
+// TLSConfig is handed to credentials.NewTLS. Every handshake asks for the latest loaded config.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.current, nil
+		},
+	}
+}
+
+// GetCertificate serves the current certificate to listeners that don't check client
+// certificates, like the HTTP gateway.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &r.current.Certificates[0], nil
+}
+
+func (r *CertReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		reloaded, err := r.reload()
+		if err != nil {
+			// keep serving the old certificate, a half written file shouldn't break handshakes
+			fmt.Printf("error reloading tls certificates : %v\n", err)
+			continue
+		}
+		if reloaded {
+			fmt.Printf("reloaded tls certificates from %s\n", r.cfg.CertFile)
+		}
+	}
+}
+
+// reload rebuilds the config when any of the files changed since the last load.
+func (r *CertReloader) reload() (bool, error) {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCaFile != "" {
+		files = append(files, r.cfg.ClientCaFile)
+	}
+	modTimes := make(map[string]time.Time)
+	changed := r.current == nil
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		modTimes[file] = info.ModTime()
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	next, err := r.build()
+	if err != nil {
+		return false, err
+	}
+	r.mu.Lock()
+	r.current = next
+	r.modTimes = modTimes
+	r.mu.Unlock()
+	return true, nil
+}
+
+func (r *CertReloader) build() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading tls key pair : %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		// GetConfigForClient replaces the config grpc prepared, so ALPN has to be set here
+		NextProtos: []string{"h2"},
+		ClientAuth: tls.NoClientCert,
+	}
+	if r.cfg.ClientCaFile == "" {
+		return tlsConfig, nil
+	}
+
+	bundle, err := os.ReadFile(r.cfg.ClientCaFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client ca bundle : %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificates found in %s", r.cfg.ClientCaFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	if strings.ToLower(r.cfg.ClientAuth) == CLIENT_AUTH_OPTIONAL {
+		// callers without a certificate fall back to tokens and api keys
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,147 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Check is one step of the pre-trade pipeline. It returns nil when the order passes.
+type Check interface {
+	Name() string
+	Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error)
+}
+
+// DefaultChecks is the pipeline in the order it runs: cheap static checks first,
+// then the ones that need to read positions or counters.
+func DefaultChecks(repo RiskRepository) []Check {
+	return []Check{
+		&RestrictedSymbolCheck{},
+		&MaxQuantityCheck{},
+		&MaxNotionalCheck{},
+		&PriceBandCheck{repo: repo},
+		&PositionLimitCheck{repo: repo},
+		&DailyValueCheck{repo: repo},
+	}
+}
+
+type RestrictedSymbolCheck struct{}
+
+func (c *RestrictedSymbolCheck) Name() string { return "restricted_symbol" }
+
+func (c *RestrictedSymbolCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	for _, symbol := range limits.RestrictedSymbols {
+		if strings.EqualFold(symbol, order.Symbol) {
+			return &Rejection{
+				Reason:  REASON_RESTRICTED_SYMBOL,
+				Message: fmt.Sprintf("%s is restricted for your account", order.Symbol),
+			}, nil
+		}
+	}
+	return nil, nil
+}
+
+type MaxQuantityCheck struct{}
+
+func (c *MaxQuantityCheck) Name() string { return "max_quantity" }
+
+func (c *MaxQuantityCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	if limits.MaxQuantity > 0 && order.Quantity > limits.MaxQuantity {
+		return &Rejection{
+			Reason:  REASON_MAX_QUANTITY,
+			Message: fmt.Sprintf("quantity %d is above the limit of %d", order.Quantity, limits.MaxQuantity),
+		}, nil
+	}
+	return nil, nil
+}
+
+type MaxNotionalCheck struct{}
+
+func (c *MaxNotionalCheck) Name() string { return "max_notional" }
+
+func (c *MaxNotionalCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	if limits.MaxOrderNotional > 0 && order.Notional() > limits.MaxOrderNotional {
+		return &Rejection{
+			Reason:  REASON_MAX_ORDER_NOTIONAL,
+			Message: fmt.Sprintf("order value %.2f is above the limit of %.2f", order.Notional(), limits.MaxOrderNotional),
+		}, nil
+	}
+	return nil, nil
+}
+
+// PriceBandCheck is the fat-finger guard, it rejects orders priced too far from the
+// reference price. Like the circuit breaker, the first price seen in a window becomes the
+// reference whether or not its order is accepted, so a real move outside the band only
+// rejects orders until the window ends.
+type PriceBandCheck struct {
+	repo RiskRepository
+}
+
+func (c *PriceBandCheck) Name() string { return "price_band" }
+
+func (c *PriceBandCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	if limits.PriceBandPct <= 0 || order.Price <= 0 {
+		return nil, nil
+	}
+	reference, ok := c.repo.GetReferencePrice(ctx, order.Symbol)
+	if !ok || reference <= 0 {
+		return nil, c.repo.SetReferencePrice(ctx, order.Symbol, order.Price, priceBandWindow)
+	}
+	move := math.Abs(order.Price-reference) / reference * 100
+	if move > limits.PriceBandPct {
+		return &Rejection{
+			Reason:  REASON_PRICE_BAND,
+			Message: fmt.Sprintf("price %.2f is %.2f%% away from reference price %.2f, band is %.2f%%", order.Price, move, reference, limits.PriceBandPct),
+		}, nil
+	}
+	return nil, nil
+}
+
+type PositionLimitCheck struct {
+	repo RiskRepository
+}
+
+func (c *PositionLimitCheck) Name() string { return "position_limit" }
+
+func (c *PositionLimitCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	if limits.MaxPositionQuantity <= 0 || order.OrderType != "BUY" {
+		return nil, nil
+	}
+	position, err := c.repo.GetPositionQuantity(ctx, order.UserId, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	// queued buys count as well, or several orders placed at once could each pass alone
+	exposure := position + order.OpenQuantity + order.Quantity
+	if exposure > limits.MaxPositionQuantity {
+		return &Rejection{
+			Reason:  REASON_POSITION_LIMIT,
+			Message: fmt.Sprintf("position in %s would be %d with open orders, limit is %d", order.Symbol, exposure, limits.MaxPositionQuantity),
+		}, nil
+	}
+	return nil, nil
+}
+
+type DailyValueCheck struct {
+	repo RiskRepository
+}
+
+func (c *DailyValueCheck) Name() string { return "daily_traded_value" }
+
+func (c *DailyValueCheck) Evaluate(ctx context.Context, order *OrderContext, limits *Limits) (*Rejection, error) {
+	if limits.MaxDailyTradedValue <= 0 {
+		return nil, nil
+	}
+	traded, err := c.repo.GetDailyTradedValue(ctx, order.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if traded+order.Notional() > limits.MaxDailyTradedValue {
+		return &Rejection{
+			Reason:  REASON_DAILY_VALUE_LIMIT,
+			Message: fmt.Sprintf("daily traded value would be %.2f, limit is %.2f", traded+order.Notional(), limits.MaxDailyTradedValue),
+		}, nil
+	}
+	return nil, nil
+}
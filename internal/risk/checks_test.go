@@ -0,0 +1,99 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeRiskRepository keeps reference prices the way redis would, expiring them on a
+// clock moved by hand.
+type fakeRiskRepository struct {
+	now        time.Time
+	references map[string]float64
+	expiresAt  map[string]time.Time
+}
+
+func newFakeRiskRepository() *fakeRiskRepository {
+	return &fakeRiskRepository{
+		now:        time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC),
+		references: make(map[string]float64),
+		expiresAt:  make(map[string]time.Time),
+	}
+}
+
+func (f *fakeRiskRepository) GetPositionQuantity(ctx context.Context, userId string, symbol string) (int32, error) {
+	return 0, nil
+}
+
+func (f *fakeRiskRepository) GetDailyTradedValue(ctx context.Context, userId string) (float64, error) {
+	return 0, nil
+}
+
+func (f *fakeRiskRepository) AddDailyTradedValue(ctx context.Context, userId string, value float64) error {
+	return nil
+}
+
+func (f *fakeRiskRepository) GetReferencePrice(ctx context.Context, symbol string) (float64, bool) {
+	price, ok := f.references[symbol]
+	if !ok || !f.now.Before(f.expiresAt[symbol]) {
+		return 0, false
+	}
+	return price, true
+}
+
+func (f *fakeRiskRepository) SetReferencePrice(ctx context.Context, symbol string, price float64, exp int) error {
+	f.references[symbol] = price
+	f.expiresAt[symbol] = f.now.Add(time.Duration(exp) * time.Minute)
+	return nil
+}
+
+func TestPriceBandRecoversAfterTheQuoteMoves(t *testing.T) {
+	repo := newFakeRiskRepository()
+	limits := defaultLimits
+	service := &RiskServiceImp{
+		repo:   repo,
+		checks: []Check{&PriceBandCheck{repo: repo}},
+		tiers:  map[string]*Limits{DEFAULT_TIER: &limits},
+	}
+	evaluate := func(userId string, price float64) *Rejection {
+		t.Helper()
+		order := &OrderContext{UserId: userId, Symbol: "AAPL", OrderType: "BUY", Quantity: 1, Price: price}
+		rejection, err := service.Evaluate(context.Background(), order)
+		if err != nil {
+			t.Fatalf("Evaluate() error = %v", err)
+		}
+		if rejection == nil {
+			if err := service.RecordOrder(context.Background(), order); err != nil {
+				t.Fatalf("RecordOrder() error = %v", err)
+			}
+		}
+		return rejection
+	}
+
+	if rejection := evaluate("u1", 100); rejection != nil {
+		t.Fatalf("first order was rejected : %v", rejection)
+	}
+	if rejection := evaluate("u1", 105); rejection != nil {
+		t.Fatalf("order within the band was rejected : %v", rejection)
+	}
+
+	// the quote jumps outside the band and stays there
+	for _, userId := range []string{"u1", "u2"} {
+		rejection := evaluate(userId, 130)
+		if rejection == nil || rejection.Reason != REASON_PRICE_BAND {
+			t.Fatalf("order of %s 30%% off the reference = %v, want a price band rejection", userId, rejection)
+		}
+	}
+
+	repo.now = repo.now.Add(priceBandWindow * time.Minute)
+	if rejection := evaluate("u2", 130); rejection != nil {
+		t.Fatalf("order at the new quote was still rejected after the window : %v", rejection)
+	}
+	if rejection := evaluate("u1", 131); rejection != nil {
+		t.Errorf("order near the new reference was rejected : %v", rejection)
+	}
+	if rejection := evaluate("u1", 100); rejection == nil {
+		t.Errorf("order at the old price passed the band around the new reference")
+	}
+}
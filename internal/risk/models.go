@@ -0,0 +1,50 @@
+package risk
+
+import "fmt"
+
+const (
+	REASON_MAX_ORDER_NOTIONAL    = "MAX_ORDER_NOTIONAL_EXCEEDED"
+	REASON_MAX_QUANTITY          = "MAX_ORDER_QUANTITY_EXCEEDED"
+	REASON_POSITION_LIMIT        = "POSITION_LIMIT_EXCEEDED"
+	REASON_DAILY_VALUE_LIMIT     = "DAILY_TRADED_VALUE_EXCEEDED"
+	REASON_PRICE_BAND            = "PRICE_OUTSIDE_BAND"
+	REASON_RESTRICTED_SYMBOL     = "SYMBOL_RESTRICTED"
+	REASON_RISK_DATA_UNAVAILABLE = "RISK_DATA_UNAVAILABLE"
+)
+
+// Limits are the thresholds for one user tier. A zero value disables that check.
+type Limits struct {
+	MaxOrderNotional    float64  `json:"maxOrderNotional"`
+	MaxQuantity         int32    `json:"maxQuantity"`
+	MaxPositionQuantity int32    `json:"maxPositionQuantity"`
+	MaxDailyTradedValue float64  `json:"maxDailyTradedValue"`
+	PriceBandPct        float64  `json:"priceBandPct"`
+	RestrictedSymbols   []string `json:"restrictedSymbols"`
+}
+
+// OrderContext is everything a check may look at for the order being placed.
+// OpenQuantity is the quantity of the user's placed orders on the same side and symbol
+// which haven't filled yet.
+type OrderContext struct {
+	UserId       string
+	Tier         string
+	Symbol       string
+	OrderType    string
+	Quantity     int32
+	Price        float64
+	OpenQuantity int32
+}
+
+func (o *OrderContext) Notional() float64 {
+	return o.Price * float64(o.Quantity)
+}
+
+// Rejection is returned by the first check an order fails.
+type Rejection struct {
+	Reason  string
+	Message string
+}
+
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("%s: %s", r.Reason, r.Message)
+}
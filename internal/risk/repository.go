@@ -0,0 +1,95 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tanmaygupta069/order-service-go/internal/holding"
+	Redis "github.com/tanmaygupta069/order-service-go/internal/pkg/redis"
+)
+
+// dailyValueExp keeps the daily counter a little past midnight UTC so late checks still see it.
+const dailyValueExp = 25 * 60
+
+// priceBandWindow is how many minutes the price band keeps its reference price. A quote
+// that moves outside the band rejects orders for at most this long.
+const priceBandWindow = 5
+
+type RiskRepository interface {
+	GetPositionQuantity(ctx context.Context, userId string, symbol string) (int32, error)
+	GetDailyTradedValue(ctx context.Context, userId string) (float64, error)
+	AddDailyTradedValue(ctx context.Context, userId string, value float64) error
+	GetReferencePrice(ctx context.Context, symbol string) (float64, bool)
+	SetReferencePrice(ctx context.Context, symbol string, price float64, exp int) error
+}
+
+type RiskRepositoryImp struct {
+	redis          Redis.RedisInterface
+	holdingService holding.HoldingService
+}
+
+func NewRiskRepository() RiskRepository {
+	return &RiskRepositoryImp{
+		redis:          Redis.NewRedisClient(),
+		holdingService: holding.NewHoldingService(),
+	}
+}
+
+func (db *RiskRepositoryImp) GetPositionQuantity(ctx context.Context, userId string, symbol string) (int32, error) {
+	existing, err := db.holdingService.GetHolding(ctx, userId, symbol)
+	if errors.Is(err, holding.ErrHoldingNotFound) {
+		// no holding yet means a flat position
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if existing == nil {
+		return 0, nil
+	}
+	return existing.Quantity, nil
+}
+
+func (db *RiskRepositoryImp) GetDailyTradedValue(ctx context.Context, userId string) (float64, error) {
+	val, err := db.redis.Get(ctx, dailyValueKey(userId))
+	if errors.Is(err, Redis.ErrKeyNotFound) {
+		// nothing traded today yet
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(val, 64)
+}
+
+func (db *RiskRepositoryImp) AddDailyTradedValue(ctx context.Context, userId string, value float64) error {
+	_, err := db.redis.IncrementFloat(ctx, dailyValueKey(userId), value, dailyValueExp)
+	return err
+}
+
+func (db *RiskRepositoryImp) GetReferencePrice(ctx context.Context, symbol string) (float64, bool) {
+	val, err := db.redis.Get(ctx, referencePriceKey(symbol))
+	if err != nil {
+		return 0, false
+	}
+	price, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, false
+	}
+	return price, true
+}
+
+func (db *RiskRepositoryImp) SetReferencePrice(ctx context.Context, symbol string, price float64, exp int) error {
+	return db.redis.Set(ctx, referencePriceKey(symbol), strconv.FormatFloat(price, 'f', -1, 64), exp)
+}
+
+func dailyValueKey(userId string) string {
+	return fmt.Sprintf("risk:daily_value:%s:%s", userId, time.Now().UTC().Format("20060102"))
+}
+
+func referencePriceKey(symbol string) string {
+	return fmt.Sprintf("risk:reference_price:%s", symbol)
+}
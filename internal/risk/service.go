@@ -0,0 +1,99 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/tanmaygupta069/order-service-go/config"
+)
+
+var cfg, _ = config.GetConfig()
+
+const DEFAULT_TIER = "default"
+
+// defaultLimits apply when no risk config file is set, or a tier isn't in it.
+var defaultLimits = Limits{
+	MaxOrderNotional:    1000000,
+	MaxQuantity:         10000,
+	MaxPositionQuantity: 100000,
+	MaxDailyTradedValue: 5000000,
+	PriceBandPct:        10,
+}
+
+type RiskService interface {
+	Evaluate(ctx context.Context, order *OrderContext) (*Rejection, error)
+	RecordOrder(ctx context.Context, order *OrderContext) error
+}
+
+type RiskServiceImp struct {
+	repo   RiskRepository
+	checks []Check
+	tiers  map[string]*Limits
+}
+
+func NewRiskService() RiskService {
+	tiers, err := LoadTierLimits(cfg.RiskConfigFile)
+	if err != nil {
+		fmt.Printf("error loading risk config, using default limits : %v\n", err)
+		tiers = map[string]*Limits{DEFAULT_TIER: &defaultLimits}
+	}
+	repo := NewRiskRepository()
+	return &RiskServiceImp{
+		repo:   repo,
+		checks: DefaultChecks(repo),
+		tiers:  tiers,
+	}
+}
+
+// LoadTierLimits reads a JSON object of tier name to Limits.
+func LoadTierLimits(path string) (map[string]*Limits, error) {
+	if path == "" {
+		return map[string]*Limits{DEFAULT_TIER: &defaultLimits}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading risk config file : %v", err)
+	}
+	tiers := make(map[string]*Limits)
+	if err = json.Unmarshal(data, &tiers); err != nil {
+		return nil, fmt.Errorf("error parsing risk config file : %v", err)
+	}
+	if _, ok := tiers[DEFAULT_TIER]; !ok {
+		tiers[DEFAULT_TIER] = &defaultLimits
+	}
+	return tiers, nil
+}
+
+func (r *RiskServiceImp) limitsFor(tier string) *Limits {
+	if limits, ok := r.tiers[tier]; ok {
+		return limits
+	}
+	return r.tiers[DEFAULT_TIER]
+}
+
+// Evaluate runs every check in order and stops at the first rejection. A check
+// that can't load its data fails the order closed.
+func (r *RiskServiceImp) Evaluate(ctx context.Context, order *OrderContext) (*Rejection, error) {
+	limits := r.limitsFor(order.Tier)
+	for _, check := range r.checks {
+		rejection, err := check.Evaluate(ctx, order, limits)
+		if err != nil {
+			fmt.Printf("risk check %s failed for user %s : %v\n", check.Name(), order.UserId, err)
+			return &Rejection{
+				Reason:  REASON_RISK_DATA_UNAVAILABLE,
+				Message: fmt.Sprintf("unable to run %s check", check.Name()),
+			}, nil
+		}
+		if rejection != nil {
+			return rejection, nil
+		}
+	}
+	return nil, nil
+}
+
+// RecordOrder updates the counters the checks read once an order has been accepted.
+func (r *RiskServiceImp) RecordOrder(ctx context.Context, order *OrderContext) error {
+	return r.repo.AddDailyTradedValue(ctx, order.UserId, order.Notional())
+}
@@ -0,0 +1,55 @@
+package security
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+	"gorm.io/gorm"
+)
+
+type SecurityRepository interface {
+	GetSecurity(ctx context.Context, symbol string) (*mysql.Securities, error)
+	SearchSecurities(ctx context.Context, term string, limit int) ([]*mysql.Securities, error)
+	UpsertSecurity(ctx context.Context, security *mysql.Securities) error
+}
+
+type SecurityRepositoryImp struct {
+	mysql *mysql.SqlServiceImplementation[mysql.Securities]
+}
+
+func NewSecurityRepository() SecurityRepository {
+	return &SecurityRepositoryImp{
+		mysql: mysql.NewSqlClient[mysql.Securities](),
+	}
+}
+
+func (db *SecurityRepositoryImp) GetSecurity(ctx context.Context, symbol string) (*mysql.Securities, error) {
+	security, err := db.mysql.GetOne(ctx, mysql.Where(mysql.Eq("symbol", symbol)))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownSymbol
+	}
+	if err != nil {
+		return nil, err
+	}
+	return security, nil
+}
+
+func (db *SecurityRepositoryImp) SearchSecurities(ctx context.Context, term string, limit int) ([]*mysql.Securities, error) {
+	securities, err := db.mysql.GetAll(ctx, mysql.Where(mysql.Or(
+		mysql.HasPrefix("symbol", term),
+		mysql.HasPrefix("name", term),
+	)).Limit(limit))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*mysql.Securities, len(securities))
+	for i := range securities {
+		result[i] = &securities[i]
+	}
+	return result, nil
+}
+
+func (db *SecurityRepositoryImp) UpsertSecurity(ctx context.Context, security *mysql.Securities) error {
+	return db.mysql.Update(ctx, security)
+}
@@ -0,0 +1,151 @@
+package security
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tanmaygupta069/order-service-go/internal/pkg/mysql"
+)
+
+var (
+	ErrUnknownSymbol     = errors.New("unknown symbol")
+	ErrSymbolNotTradable = errors.New("symbol is not tradable")
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+// csvColumns is the header expected in the securities file.
+var csvColumns = []string{"symbol", "name", "exchange", "currency", "lot_size", "tick_size", "tradable", "sector"}
+
+type SecurityService interface {
+	GetSecurity(ctx context.Context, symbol string) (*mysql.Securities, error)
+	ValidateSymbol(ctx context.Context, symbol string) (*mysql.Securities, error)
+	SearchSymbols(ctx context.Context, term string, limit int) ([]*mysql.Securities, error)
+	LoadFromCSV(ctx context.Context, path string) (int, error)
+}
+
+type SecurityServiceImp struct {
+	repo SecurityRepository
+}
+
+func NewSecurityService() SecurityService {
+	return &SecurityServiceImp{
+		repo: NewSecurityRepository(),
+	}
+}
+
+func (r *SecurityServiceImp) GetSecurity(ctx context.Context, symbol string) (*mysql.Securities, error) {
+	return r.repo.GetSecurity(ctx, strings.ToUpper(symbol))
+}
+
+// ValidateSymbol returns the security for symbol if it exists and can be traded.
+func (r *SecurityServiceImp) ValidateSymbol(ctx context.Context, symbol string) (*mysql.Securities, error) {
+	security, err := r.GetSecurity(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if !security.Tradable {
+		return nil, ErrSymbolNotTradable
+	}
+	return security, nil
+}
+
+func (r *SecurityServiceImp) SearchSymbols(ctx context.Context, term string, limit int) ([]*mysql.Securities, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return []*mysql.Securities{}, nil
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	return r.repo.SearchSecurities(ctx, term, limit)
+}
+
+// LoadFromCSV upserts every row of the securities file and returns how many were loaded.
+func (r *SecurityServiceImp) LoadFromCSV(ctx context.Context, path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("error opening securities file : %v", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("error reading securities header : %v", err)
+	}
+	index := make(map[string]int)
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range csvColumns {
+		if _, ok := index[column]; !ok {
+			return 0, fmt.Errorf("securities file is missing column %s", column)
+		}
+	}
+
+	count := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("error reading securities line %d : %v", line, err)
+		}
+		security, err := parseSecurity(record, index)
+		if err != nil {
+			return count, fmt.Errorf("invalid security on line %d : %v", line, err)
+		}
+		if err = r.repo.UpsertSecurity(ctx, security); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func parseSecurity(record []string, index map[string]int) (*mysql.Securities, error) {
+	field := func(name string) string {
+		return strings.TrimSpace(record[index[name]])
+	}
+	symbol := strings.ToUpper(field("symbol"))
+	if symbol == "" {
+		return nil, fmt.Errorf("symbol can't be empty")
+	}
+	lotSize, err := strconv.ParseInt(field("lot_size"), 10, 32)
+	if err != nil || lotSize <= 0 {
+		return nil, fmt.Errorf("lot_size must be a positive integer")
+	}
+	tickSize, err := strconv.ParseFloat(field("tick_size"), 64)
+	if err != nil || tickSize <= 0 {
+		return nil, fmt.Errorf("tick_size must be a positive number")
+	}
+	tradable, err := strconv.ParseBool(field("tradable"))
+	if err != nil {
+		return nil, fmt.Errorf("tradable must be true or false")
+	}
+	return &mysql.Securities{
+		Symbol:   symbol,
+		Name:     field("name"),
+		Exchange: strings.ToUpper(field("exchange")),
+		Currency: strings.ToUpper(field("currency")),
+		LotSize:  int32(lotSize),
+		TickSize: tickSize,
+		Tradable: tradable,
+		Sector:   field("sector"),
+	}, nil
+}